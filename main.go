@@ -1,101 +1,447 @@
 package main
 
 import (
+	"bufio"
+	"flag"
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 
 	"simplecalc/pkg/parser"
+	"simplecalc/pkg/parser/function"
+	"simplecalc/pkg/parser/unit"
+	"simplecalc/pkg/parser/value"
 	"simplecalc/pkg/terminal"
 )
 
-func printRusults(results []float64) {
-	for _, result := range results {
-		// Minimize digits and no scientific notation
-		// if number is greater than 1e6-1 or less than -1e6+1
-		fmt.Printf("%s\r\n", strconv.FormatFloat(result, 'f', -1, 64))
+// printResults prints the results of the expressions,
+// numbered like "[3]: 42" for "$3" if the outputs are numbered
+func (s *settings) printResults(results []value.Value) {
+	first := s.parser.ResultCount() - len(results) + 1
+	for i, result := range results {
+		// Numbers are printed with minimized digits and no scientific notation,
+		// booleans from comparisons are printed as true or false,
+		// rational numbers are printed with the format
+		if s.numbered {
+			s.printf("[%d]: %s", first+i, value.FormatValue(result, s.format))
+		} else {
+			s.printf("%s", value.FormatValue(result, s.format))
+		}
 	}
 }
 
-func help() {
+func (s *settings) help() {
 	msg := `Simple Calculator
 Commands:
   - help: Show this help message
   - exit: Exit the calculator
-  - history: Show the command history
-  - clear: Clear the history
+  - history: Show the numbered command history, only in the interactive terminal
+  - !!, !<n>, !<prefix>: Run the last entry, the entry numbered n, or the last entry starting with the prefix
+    in the history, Ctrl-R searches the history and Ctrl-G cancels the search
+  - Tab: Complete the name of the variable, function, constant or command, press again for the next one
+  - clear: Clear the history, including the history file, only in the interactive terminal
+  - mode [float|exact|rational]: Show or switch the evaluation mode, exact mode uses arbitrary precision
+    and rational mode shows fractions
+  - format [decimal|fraction|mixed|polar]: Show or set how the rational and complex results are displayed
+  - echo [on|off]: Show or set whether the values of assignments like x = 5 are printed
+  - implicit [equal|tight]: Show or set the precedence of implicit multiplication like 2x,
+    equal to "*" and "/" or tighter than them
+  - precision [<digits>]: Show or set the significant digits of exact mode (1-%d)
   - <expression>: Evaluate the expression
   - <var> = <expression>: Assign the expression to the variable
+  - <var> += <expression>: Update the variable with the operator, also -=, *=, /= and **=
   - <var>: Show the value of the variable
+  - ans, _: The last result, $<n> is the result numbered n like [3]: 42 is $3
+  - reset: Clear the results referred by ans, _ and $<n>
   - <expression1>; <expression2>; ...: Evaluate multiple expressions
   - <var1> = <expression1>; <var2> = <expression2>; ...: Assign multiple variables
+  - <func>(<expression1>, <expression2>, ...): Call a built-in or user-defined function
+  - <func>(<param1>, <param2>, ...) = <expression>: Define a function
+  - <number> <unit>: A number with unit like 5 km
+  - <expression> in <unit>: Convert the expression to the unit, "to" is the same as "in"
+  - [<expression1>, <expression2>, ...]: A list of values
+  - <list>[<index>]: The element of the list at the index, negative indices count from the end
+  - <matrix> @ <matrix>: Multiply the matrices like [[1, 2], [3, 4]] @ [5, 6]
+Functions:
+  %s
+Constants:
+  %s
+Units:
+  %s
 Examples:
   >>> 2 + 6
   >>> x = 7 + 8
   >>> y = x * 2
+  >>> y += 1; y **= 2
+  >>> a = b = 3; c = (d = 2) * a
   >>> z = x / (2.5 * (-6 + y))
   >>> z
+  >>> ans * 2; $1 + _
   >>> a = 2; b = -17; c = -b / (a + -12); c
+  >>> 17 // 5; 17 % 5; -17 rem 5
+  >>> 5!; 2 ** 3!; 200 * 15%
+  >>> x > 3 && y <= 2 || !(z == 0)
+  >>> x == 0 ? 0 : 1 / x
+  >>> (reg >> 4) & 15 | 1 << 7
+  >>> sqrt(x) + log(8, 2) * pi
+  >>> 2x + 3(x - 1) + (x)(y)
+  >>> area(r) = 3.14159 * r ** 2
+  >>> area(2) + area(y)
+  >>> mode exact
+  >>> 0.1 + 0.2; 2 ** 100; 1 / 3
+  >>> mode rational
+  >>> 1 / 3 + 1 / 6; (2 / 3) ** 3
+  >>> format mixed
+  >>> (3 + 4i) * (1 - 2i); sqrt(-1)
+  >>> format polar
+  >>> 5 km / 20 min in km/h
+  >>> 100 degC to degF
+  >>> v = [1, 2, 3]; v * 2 + [10, 20, 30]; sum(v); v[-1]
+  >>> mean(1, 4, 9); median(v); stdev(v); percentile(90, v, 4)
+  >>> A = [[2, 1], [1, 3]]; A @ A; det(A); inverse(A); solve(A, [3, 5])
 `
+	msg = fmt.Sprintf(msg,
+		parser.MaxPrecision,
+		strings.Join(function.Names(), ", "),
+		strings.Join(parser.ConstantNames(), ", "),
+		strings.Join(unit.Names(), ", "))
 
-	// Add CRLF to each line
-	lines := strings.ReplaceAll(msg, "\n", "\r\n")
+	// Add CRLF to each line in the interactive terminal
+	lines := strings.ReplaceAll(msg, "\n", s.newline)
 
-	fmt.Print(lines)
+	fmt.Fprint(s.out, lines)
 }
 
-func main() {
+// settings are the options changed by the commands
+// and where the results are printed
+type settings struct {
+	parser *parser.Parser
+	format value.Format
+	// numbered prints the results with their numbers in the interactive terminal
+	numbered bool
+
+	// terminal has the history, which is nil in the batch mode
+	terminal *terminal.Terminal
+
+	// infoOut is for the status of the commands like "Mode: exact",
+	// which is stderr in the batch mode to keep stdout for the results.
+	// newline is CRLF in the raw mode of the interactive terminal
+	out     io.Writer
+	errOut  io.Writer
+	infoOut io.Writer
+	newline string
+}
+
+func (s *settings) printf(format string, a ...any) {
+	fmt.Fprintf(s.out, format+s.newline, a...)
+}
+
+func (s *settings) infof(format string, a ...any) {
+	fmt.Fprintf(s.infoOut, format+s.newline, a...)
+}
+
+func (s *settings) errorf(format string, a ...any) {
+	fmt.Fprintf(s.errOut, format+s.newline, a...)
+}
+
+// configure handles the commands changing the settings,
+// it returns false if the input is not such a command.
+func (s *settings) configure(input string) (bool, error) {
+	fields := strings.Fields(input)
+	if len(fields) == 0 || len(fields) > 2 {
+		return false, nil
+	}
+
+	switch fields[0] {
+	case "mode":
+		if len(fields) == 2 {
+			mode, err := parser.ParseMode(fields[1])
+			if err != nil {
+				return true, fmt.Errorf("error setting mode: %w", err)
+			}
+			s.parser.SetMode(mode)
+			s.format = mode.Format()
+		}
+		s.infof("Mode: %s", s.parser.Mode())
+	case "precision":
+		if len(fields) == 2 {
+			digits, err := strconv.Atoi(fields[1])
+			if err == nil {
+				err = s.parser.SetPrecision(digits)
+			}
+			if err != nil {
+				return true, fmt.Errorf("error setting precision: %w", err)
+			}
+		}
+		s.infof("Precision: %d digits", s.parser.Precision())
+	case "format":
+		if len(fields) == 2 {
+			format, err := value.ParseFormat(fields[1])
+			if err != nil {
+				return true, fmt.Errorf("error setting format: %w", err)
+			}
+			s.format = format
+		}
+		s.infof("Format: %s", s.format)
+	case "echo":
+		if len(fields) == 2 {
+			switch fields[1] {
+			case "on":
+				s.parser.SetEchoAssignments(true)
+			case "off":
+				s.parser.SetEchoAssignments(false)
+			default:
+				return true, fmt.Errorf("error setting echo: '%s', must be on or off", fields[1])
+			}
+		}
+		if s.parser.EchoAssignments() {
+			s.infof("Echo: on")
+		} else {
+			s.infof("Echo: off")
+		}
+	case "implicit":
+		if len(fields) == 2 {
+			juxtaposition, err := parser.ParseJuxtaposition(fields[1])
+			if err != nil {
+				return true, fmt.Errorf("error setting implicit multiplication: %w", err)
+			}
+			s.parser.SetJuxtaposition(juxtaposition)
+		}
+		s.infof("Implicit multiplication: %s", s.parser.Juxtaposition())
+	default:
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// commands are completed by Tab at the beginning of the line
+var commands = []string{"help", "exit", "history", "clear", "reset", "mode", "precision", "format", "echo", "implicit"}
+
+var (
+	// errExit is returned by run when the input is the exit command
+	errExit = fmt.Errorf("exit")
+	// errNoHistory is returned by the history commands in the batch mode
+	errNoHistory = fmt.Errorf("no history outside of the interactive terminal")
+)
+
+// run handles the commands and evaluates the expressions of the input
+func (s *settings) run(input string) error {
+	switch input {
+	case "":
+		return nil
+	case "exit":
+		return errExit
+	case "help":
+		s.help()
+		return nil
+	case "reset":
+		s.parser.ResetResults()
+		s.infof("Results cleared")
+		return nil
+	case "history", "clear":
+		if s.terminal == nil {
+			return fmt.Errorf("%w: '%s'", errNoHistory, input)
+		}
+		if input == "history" {
+			s.printf("%s", s.terminal.ListHistory())
+		} else {
+			s.terminal.ClearHistory()
+			s.infof("History cleared")
+		}
+		return nil
+	}
+
+	if ok, err := s.configure(input); ok {
+		return err
+	}
+
+	// Single input may has multiple expressions separated by semicolons
+	results, err := s.parser.Parse(input)
+	if err != nil {
+		if err == parser.ErrNilExpression {
+			return nil
+		}
+		return fmt.Errorf("error from parser: %w", err)
+	}
+
+	s.printResults(results)
+	return nil
+}
+
+// runScript evaluates the input line by line and reports the errors with
+// the line numbers of the named input, the lines starting with "#" are comments.
+// It returns false if any line fails.
+func (s *settings) runScript(name string, r io.Reader) (bool, error) {
+	ok := true
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		input := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(input, "#") {
+			continue
+		}
+
+		if err := s.run(input); err != nil {
+			if err == errExit {
+				return ok, errExit
+			}
+			if name == "" {
+				s.errorf("%v", err)
+			} else {
+				s.errorf("%s:%d: %v", name, line, err)
+			}
+			ok = false
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("error reading %s: %w", name, err)
+	}
+
+	return ok, nil
+}
+
+// source is an expression from -e or a script file from -f
+type source struct {
+	expr string
+	file string
+}
+
+// batch evaluates the sources in order without the interactive terminal,
+// it returns the exit code: 1 if any expression fails, 2 if a file can't be read.
+func batch(s *settings, sources []source, stdin io.Reader) int {
+	code := 0
+	for _, src := range sources {
+		var ok bool
+		var err error
+		switch {
+		case src.file == "":
+			ok, err = s.runScript("", strings.NewReader(src.expr))
+		case src.file == "-":
+			ok, err = s.runScript("<stdin>", stdin)
+		default:
+			var f *os.File
+			f, err = os.Open(src.file)
+			if err != nil {
+				s.errorf("error opening script: %v", err)
+				return 2
+			}
+			ok, err = s.runScript(src.file, f)
+			f.Close()
+		}
+
+		if !ok {
+			code = 1
+		}
+		if err == errExit {
+			return code
+		}
+		if err != nil {
+			s.errorf("%v", err)
+			return 2
+		}
+	}
+
+	return code
+}
+
+func interactive(s *settings, historyFile string, historySize int) {
 	t, err := terminal.NewTerminal(os.Stdin, ">>> ")
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error creating terminal: %v\r\n", err)
 		return
 	}
 	defer t.Restore()
+	s.terminal = t
 
-	p := parser.NewParser()
+	t.SetCompleter(commands, func() []string {
+		return slices.Concat(s.parser.VariableNames(), s.parser.FunctionNames(),
+			function.Names(), parser.ConstantNames())
+	})
+
+	// The history is only kept in memory without the file
+	t.SetHistorySize(historySize)
+	if historyFile != "" {
+		if err := t.LoadHistory(historyFile); err != nil {
+			s.errorf("error loading history: %v", err)
+		}
+	}
+	historyErr := false
 
-	fmt.Printf("Enter an expression (or 'exit' to quit):\r\n")
+	s.printf("Enter an expression (or 'exit' to quit):")
 	for {
 		input, err := t.ReadLine()
 		if err != nil {
 			if err == io.EOF {
-				fmt.Printf("^c\r\n")
+				s.printf("^c")
 				return
 			}
-			fmt.Fprintf(os.Stderr, "error reading input: %v\r\n", err)
+			s.errorf("error reading input: %v", err)
 			continue
 		}
 		input = strings.TrimSpace(input)
 
-		switch input {
-		case "":
-			continue
-		case "exit":
-			return
-		case "help":
-			help()
-			continue
-		case "history":
-			fmt.Printf("%s\r\n", t.GetHistory())
-			continue
-		case "clear":
-			t.ClearHistory()
-			fmt.Printf("History cleared\r\n")
-			continue
+		if err := t.HistoryError(); err != nil && !historyErr {
+			s.errorf("error saving history: %v", err)
+			historyErr = true
 		}
 
-		// Single input may has multiple expressions separated by semicolons
-		results, err := p.Parse(input)
-		if err != nil {
-			if err == parser.ErrNilExpression {
-				continue
+		if err := s.run(input); err != nil {
+			if err == errExit {
+				return
 			}
-			fmt.Fprintf(os.Stderr, "error from parser: %v\r\n", err)
-			continue
+			s.errorf("%v", err)
 		}
+	}
+}
 
-		printRusults(results)
+func main() {
+	var sources []source
+	flag.Func("e", "evaluate the `expression`, may be repeated", func(expr string) error {
+		sources = append(sources, source{expr: expr})
+		return nil
+	})
+	flag.Func("f", "evaluate the script `file` line by line, - for stdin", func(file string) error {
+		sources = append(sources, source{file: file})
+		return nil
+	})
+	// The history file is disabled by an empty path
+	historyFile, _ := terminal.HistoryPath()
+	flag.StringVar(&historyFile, "history", historyFile, "the history `file`, also set by $"+terminal.HistoryEnv)
+	historySize := flag.Int("history-size", terminal.DefaultHistorySize, "the maximum number of history entries")
+	flag.Parse()
+	if flag.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "unexpected arguments: %s\n", strings.Join(flag.Args(), " "))
+		flag.Usage()
+		os.Exit(2)
 	}
+	if *historySize < 1 {
+		fmt.Fprintf(os.Stderr, "invalid history size: %d, must be positive\n", *historySize)
+		os.Exit(2)
+	}
+
+	p := parser.NewParser()
+	s := &settings{
+		parser:  p,
+		format:  p.Mode().Format(),
+		out:     os.Stdout,
+		errOut:  os.Stderr,
+		infoOut: os.Stderr,
+		newline: "\n",
+	}
+
+	// Piped or redirected stdin is evaluated like a script
+	if len(sources) == 0 && !terminal.IsTerminal(os.Stdin) {
+		sources = append(sources, source{file: "-"})
+	}
+	if len(sources) > 0 {
+		os.Exit(batch(s, sources, os.Stdin))
+	}
+
+	s.infoOut = os.Stdout
+	s.newline = "\r\n"
+	s.numbered = true
+	interactive(s, historyFile, *historySize)
 }
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"simplecalc/pkg/parser"
+)
+
+func newBatchSettings() (*settings, *bytes.Buffer, *bytes.Buffer) {
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	p := parser.NewParser()
+	return &settings{
+		parser:  p,
+		format:  p.Mode().Format(),
+		out:     out,
+		errOut:  errOut,
+		infoOut: errOut,
+		newline: "\n",
+	}, out, errOut
+}
+
+func TestRunScript(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantOk     bool
+		wantExit   bool
+		wantOut    string
+		wantErrOut string
+	}{
+		{
+			name:    "results and comments",
+			input:   "# comment\n1 + 1\n\nx = 3; x * 2\n  # indented comment\n",
+			wantOk:  true,
+			wantOut: "2\n6\n",
+		},
+		{
+			name:       "errors with line numbers",
+			input:      "1 / 0\n2\ny\n",
+			wantOk:     false,
+			wantOut:    "2\n",
+			wantErrOut: "script:1: error from parser",
+		},
+		{
+			name:     "exit stops the script",
+			input:    "1\nexit\n2\n",
+			wantOk:   true,
+			wantExit: true,
+			wantOut:  "1\n",
+		},
+		{
+			name:       "settings are not results",
+			input:      "mode rational\n1 / 3\nreset\n",
+			wantOk:     true,
+			wantOut:    "1/3\n",
+			wantErrOut: "Mode: rational\nResults cleared\n",
+		},
+		{
+			name:       "no history",
+			input:      "history\nclear\n",
+			wantOk:     false,
+			wantErrOut: "script:2: " + errNoHistory.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, out, errOut := newBatchSettings()
+			ok, err := s.runScript("script", strings.NewReader(tt.input))
+			if ok != tt.wantOk {
+				t.Errorf("runScript() ok = %v, want %v, stderr %q", ok, tt.wantOk, errOut)
+			}
+			if (err == errExit) != tt.wantExit {
+				t.Errorf("runScript() error = %v, want exit %v", err, tt.wantExit)
+			}
+			if got := out.String(); got != tt.wantOut {
+				t.Errorf("stdout = %q, want %q", got, tt.wantOut)
+			}
+			if got := errOut.String(); !strings.Contains(got, tt.wantErrOut) {
+				t.Errorf("stderr = %q, want containing %q", got, tt.wantErrOut)
+			}
+		})
+	}
+}
+
+func TestBatch(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "script.calc")
+	if err := os.WriteFile(script, []byte("x = 2\n# comment\nx ** 10\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		sources  []source
+		stdin    string
+		wantCode int
+		wantOut  string
+	}{
+		{
+			name:     "expressions",
+			sources:  []source{{expr: "1 + 1"}, {expr: "ans * 3"}},
+			wantCode: 0,
+			wantOut:  "2\n6\n",
+		},
+		{
+			name:     "script file and expression",
+			sources:  []source{{file: script}, {expr: "x + $1"}},
+			wantCode: 0,
+			wantOut:  "1024\n1026\n",
+		},
+		{
+			name:     "stdin",
+			sources:  []source{{file: "-"}},
+			stdin:    "3 * 4\n",
+			wantCode: 0,
+			wantOut:  "12\n",
+		},
+		{
+			name:     "error continues",
+			sources:  []source{{expr: "1 / 0"}, {expr: "5"}},
+			wantCode: 1,
+			wantOut:  "5\n",
+		},
+		{
+			name:     "exit stops all sources",
+			sources:  []source{{expr: "y"}, {expr: "exit"}, {expr: "5"}},
+			wantCode: 1,
+			wantOut:  "",
+		},
+		{
+			name:     "missing file",
+			sources:  []source{{expr: "5"}, {file: filepath.Join(dir, "missing.calc")}, {expr: "6"}},
+			wantCode: 2,
+			wantOut:  "5\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, out, errOut := newBatchSettings()
+			if code := batch(s, tt.sources, strings.NewReader(tt.stdin)); code != tt.wantCode {
+				t.Errorf("batch() = %d, want %d, stderr %q", code, tt.wantCode, errOut)
+			}
+			if got := out.String(); got != tt.wantOut {
+				t.Errorf("stdout = %q, want %q", got, tt.wantOut)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package parser
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+	"strconv"
+
+	"simplecalc/pkg/parser/value"
+)
+
+var ErrAssignConstant = fmt.Errorf("cannot assign to constant")
+
+// constants is a read-only namespace consulted before the variables,
+// so these names can't be assigned or used as function parameters.
+// The values have MaxPrecision significant digits for the exact mode.
+var constants = map[string]string{
+	"pi":  "3.141592653589793238462643383279502884197169399375105820974944592307816406286208998628034825342117068",
+	"e":   "2.718281828459045235360287471352662497757247093699959574966967627724076630353547594571382178525166427",
+	"tau": "6.283185307179586476925286766559005768394338798750211641949889184615632812572417997256069650684234136",
+	"phi": "1.618033988749894848204586834365638117720309179805762862135448622705260462818902449707207204189391137",
+	"inf": "+Inf",
+}
+
+// GetConstant returns the value of the named constant
+func GetConstant(name string) (float64, bool) {
+	text, ok := constants[name]
+	if !ok {
+		return 0, false
+	}
+
+	val, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		// Must be a bug from the table above, don't recover it
+		panic(fmt.Sprintf("invalid constant '%s': %s", name, err))
+	}
+
+	return val, true
+}
+
+// getExactConstant returns the value of the named constant
+// with the number of significant decimal digits
+func getExactConstant(name string, digits uint) (value.Value, bool) {
+	text, ok := constants[name]
+	if !ok {
+		return nil, false
+	}
+
+	val, _, err := big.ParseFloat(text, 10, value.PrecisionBits(digits), big.ToNearestEven)
+	if err != nil {
+		// Must be a bug from the table above, don't recover it
+		panic(fmt.Sprintf("invalid constant '%s': %s", name, err))
+	}
+
+	return value.NewBigFloat(val, digits), true
+}
+
+// IsConstant checks if the name is a constant
+func IsConstant(name string) bool {
+	_, ok := constants[name]
+	return ok
+}
+
+// ConstantNames returns the sorted names of all constants
+func ConstantNames() []string {
+	names := make([]string, 0, len(constants))
+	for name := range constants {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
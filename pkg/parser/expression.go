@@ -1,10 +1,18 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"math/big"
+	"slices"
 	"strconv"
+	"strings"
 
+	"simplecalc/pkg/parser/function"
 	"simplecalc/pkg/parser/operator"
+	"simplecalc/pkg/parser/unit"
+	"simplecalc/pkg/parser/value"
 )
 
 type ExprType uint8
@@ -12,6 +20,10 @@ type ExprType uint8
 const (
 	ExprTypeAtomic ExprType = iota
 	ExprTypeOperation
+	ExprTypeCall
+	ExprTypeConditional
+	ExprTypeList
+	ExprTypeIndex
 )
 
 var (
@@ -19,42 +31,61 @@ var (
 	ErrMissingLeftParenthesis  = fmt.Errorf("missing left parenthesis")
 	ErrMissingRightParenthesis = fmt.Errorf("missing right parenthesis")
 	ErrNumOutOfRange           = fmt.Errorf("number is too large/small that lost percision in float64")
+	ErrMissingArgument         = fmt.Errorf("missing argument")
+	ErrUnexpectedComma         = fmt.Errorf("unexpected comma outside of function call")
+	ErrMissingColon            = fmt.Errorf("missing colon of conditional expression")
+	ErrUnexpectedColon         = fmt.Errorf("unexpected colon outside of conditional expression")
+	ErrMissingBranch           = fmt.Errorf("missing branch of conditional expression")
+	ErrMissingLeftBracket      = fmt.Errorf("missing left bracket")
+	ErrMissingRightBracket     = fmt.Errorf("missing right bracket")
+	ErrMissingIndex            = fmt.Errorf("missing index")
+	ErrInvalidIndex            = fmt.Errorf("invalid index")
+	ErrIndexOutOfRange         = fmt.Errorf("index out of range")
+	ErrUndefinedVariable       = fmt.Errorf("undefined variable")
 )
 
 type Expression struct {
-	typ          ExprType
-	value        float64
+	typ       ExprType
+	value     float64
+	exact     *big.Rat
+	imaginary bool
+	// integer is the integer literal like 42, which must be exact in float64
+	integer bool
+	unit    string
+	// juxtaposed is the number right before the unit like "2m",
+	// which is the multiplication if the unit is shadowed by a variable
+	juxtaposed   bool
 	variableName string
 	op           operator.Operator
 	left         *Expression
 	right        *Expression
+	funcName     string
+	// args holds the arguments of a call or the elements of a list
+	args []*Expression
+	cond *Expression
 }
 
 func (e *Expression) GetType() ExprType {
 	return e.typ
 }
 
-func (e *Expression) Evaluate(variables map[string]float64) (float64, error) {
-	value, err := e.evaluate(variables)
-	if err != nil {
-		return 0, err
-	}
-
-	// Check if the value is too large/small
-	if e.isNumOutOfRange(value) {
-		return 0, ErrNumOutOfRange
-	}
+func (e *Expression) Evaluate(variables map[string]value.Value) (value.Value, error) {
+	return e.evaluateIn(newScope(variables, nil))
+}
 
-	return value, nil
+// evaluateIn evaluates the expression with variables and user-defined
+// functions from the scope.
+func (e *Expression) evaluateIn(s *scope) (value.Value, error) {
+	return e.evaluate(s)
 }
 
-func (e *Expression) evaluate(variables map[string]float64) (float64, error) {
+func (e *Expression) evaluate(s *scope) (value.Value, error) {
 	if e == nil {
-		return 0, ErrNilExpression
+		return nil, ErrNilExpression
 	}
 
 	// If the expression is atomic, get value
-	// from the value field or from the variables map
+	// from the value field or from the variables of the scope
 	if e.IsAtom() {
 		if e.IsAtomVarName() {
 			varName := e.GetVarName()
@@ -66,43 +97,250 @@ func (e *Expression) evaluate(variables map[string]float64) (float64, error) {
 				negative = true       // Set the negative flag
 			}
 
-			if val, ok := variables[varName]; ok {
+			if val, ok := s.lookup(varName); ok {
 				if negative {
-					val = -val
+					negated, err := value.Negate(val)
+					if err != nil {
+						return nil, fmt.Errorf("failed to negate variable '%s': %w", varName, err)
+					}
+					val = negated
 				}
 
 				return val, nil
 			}
-			return 0, fmt.Errorf("undefined variable '%s'", varName)
+			if isResultReference(varName) {
+				return nil, fmt.Errorf("%w: '%s'", ErrNoResult, varName)
+			}
+			return nil, fmt.Errorf("%w: '%s'", ErrUndefinedVariable, varName)
+		}
+
+		// Numbers with unit like "5 km" are always float64 even in the exact modes
+		if e.unit != "" {
+			// The variable with the same name shadows the unit, so "2m" is "2 * m"
+			if e.juxtaposed && s.isVariable(e.unit) {
+				num := newAtomicNumExpression(e.value, e.exact)
+				num.integer = e.integer
+				return newOperationExpression(operator.GetOperator("*"), num, newAtomicVarExpression(e.unit)).evaluate(s)
+			}
+
+			u, err := unit.Lookup(e.unit)
+			if err != nil {
+				return nil, err
+			}
+			return value.NewQuantity(e.value, u), nil
+		}
+
+		// Imaginary numbers are always complex128 even in the exact modes
+		if e.imaginary {
+			return value.FromComplex(complex(0, e.value)), nil
+		}
+
+		if s.exact {
+			return value.NewRational(e.exact, s.digits), nil
+		}
+
+		// Check if the integer literal is too large/small to be exact
+		if e.integer && isNumOutOfRange(value.Float(e.value)) {
+			return nil, ErrNumOutOfRange
+		}
+
+		return value.Float(e.value), nil
+	}
+
+	// If the expression is a function call, evaluate the arguments
+	// and call the function with them
+	if e.IsCall() {
+		return e.evaluateCall(s)
+	}
+
+	if e.IsList() {
+		list := make(value.List, 0, len(e.args))
+		for i, elem := range e.args {
+			val, err := elem.evaluateIn(s)
+			if err != nil {
+				return nil, fmt.Errorf("failed to evaluate element %d of list: %w", i+1, err)
+			}
+			list = append(list, val)
 		}
+		return list, nil
+	}
+
+	if e.IsIndex() {
+		return e.evaluateIndex(s)
+	}
 
-		return e.value, nil
+	// If the expression is a conditional, evaluate the condition
+	// and then only the selected branch
+	if e.IsConditional() {
+		cond, err := e.cond.evaluateIn(s)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate condition: %w", err)
+		}
+		if value.Truthy(cond) {
+			return e.left.evaluateIn(s)
+		}
+		return e.right.evaluateIn(s)
+	}
+
+	// Assign the value of the right expression to the variable,
+	// the value is also the result, so "a = b = 3" assigns both
+	if e.IsOPAssignment() || e.IsOPCompoundAssignment() {
+		return e.evaluateAssignment(s)
 	}
 
 	// If the expression is an operation, evaluate the left and right expressions
 	if e.left == nil {
-		return 0, fmt.Errorf("no left expression for operation: %s", e.op)
+		return nil, fmt.Errorf("no left expression for operation: %s", e.op)
 	}
-	oprands := make([]float64, 0, 2)
-	leftValue, err := e.left.Evaluate(variables)
+	oprands := make([]value.Value, 0, 2)
+	leftValue, err := e.left.evaluateIn(s)
 	if err != nil {
-		return 0, fmt.Errorf("failed to evaluate left expression: %w", err)
+		return nil, fmt.Errorf("failed to evaluate left expression: %w", err)
 	}
 	oprands = append(oprands, leftValue)
+
+	// Short-circuit the logical operators, the right expression
+	// is only evaluated if the left one can't decide the result
+	if e.op != nil && e.op.Is("&&") && !value.Truthy(leftValue) {
+		return value.Bool(false), nil
+	}
+	if e.op != nil && e.op.Is("||") && value.Truthy(leftValue) {
+		return value.Bool(true), nil
+	}
+
 	if e.right != nil {
-		rightValue, err := e.right.Evaluate(variables)
+		rightValue, err := e.right.evaluateIn(s)
 		if err != nil {
-			return 0, fmt.Errorf("failed to evaluate right expression: %w", err)
+			return nil, fmt.Errorf("failed to evaluate right expression: %w", err)
 		}
 		oprands = append(oprands, rightValue)
 	}
 
 	op := e.op
 	if op == nil {
-		return 0, fmt.Errorf("operator is nil for expression: %s", e)
+		return nil, fmt.Errorf("operator is nil for expression: %s", e)
+	}
+
+	result, err := op.Evaluate(oprands)
+	if err != nil {
+		return nil, err
+	}
+	if isOverflow(oprands, result) {
+		return nil, ErrNumOutOfRange
 	}
 
-	return op.Evaluate(oprands)
+	return result, nil
+}
+
+// evaluateCall calls the user-defined function from the scope,
+// or the built-in function if there is no such user-defined function.
+func (e *Expression) evaluateCall(s *scope) (value.Value, error) {
+	args := make([]value.Value, 0, len(e.args))
+	for i, arg := range e.args {
+		val, err := arg.evaluateIn(s)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate argument %d of '%s': %w", i+1, e.funcName, err)
+		}
+		args = append(args, val)
+	}
+
+	if fn, ok := s.functions[e.funcName]; ok {
+		if len(args) != len(fn.params) {
+			return nil, fmt.Errorf(
+				"%w: must have exactly %d arguments for '%s' function",
+				function.ErrInvalidArgumentCount,
+				len(fn.params),
+				fn.name)
+		}
+
+		inner, err := s.call(fn, args)
+		if err != nil {
+			return nil, err
+		}
+
+		val, err := fn.body.evaluateIn(inner)
+		if errors.Is(err, ErrMaxCallDepth) {
+			// Don't wrap the error from every level of the recursion
+			return nil, maxCallDepthError(fn)
+		}
+
+		return val, err
+	}
+
+	fn, err := function.GetFunction(e.funcName)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := fn.Evaluate(args)
+	if err != nil {
+		return nil, err
+	}
+	if isOverflow(args, result) {
+		return nil, ErrNumOutOfRange
+	}
+
+	return result, nil
+}
+
+// evaluateAssignment assigns the value of the right expression
+// to the variable of the left one and returns the value.
+func (e *Expression) evaluateAssignment(s *scope) (value.Value, error) {
+	varName := e.left.GetVarName()
+	if varName == "" || isResultReference(varName) {
+		return nil, fmt.Errorf("%w: '%s'", ErrInvalidAssignment, e.left)
+	}
+	if IsConstant(varName) {
+		return nil, fmt.Errorf("%w: '%s'", ErrAssignConstant, varName)
+	}
+
+	// Only the variables can be updated, not the results or the units like km
+	if e.IsOPCompoundAssignment() {
+		if !s.isVariable(varName) {
+			return nil, fmt.Errorf("%w: '%s' of '%s'", ErrUndefinedVariable, varName, e.op)
+		}
+		e = e.desugarCompoundAssignment()
+	}
+
+	val, err := e.right.evaluateIn(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate value of '%s': %w", varName, err)
+	}
+	s.assign(varName, val)
+
+	return val, nil
+}
+
+// evaluateIndex returns the element of the list at the index,
+// a negative index counts from the end like v[-1] is the last element.
+func (e *Expression) evaluateIndex(s *scope) (value.Value, error) {
+	target, err := e.left.evaluateIn(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate indexed expression: %w", err)
+	}
+	list, ok := target.(value.List)
+	if !ok {
+		return nil, fmt.Errorf("%w: cannot index %s '%s'", ErrInvalidIndex, target.Kind(), target)
+	}
+
+	index, err := e.right.evaluateIn(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate index: %w", err)
+	}
+	num, err := value.ToFloat(index)
+	if err != nil || num != math.Trunc(num) {
+		return nil, fmt.Errorf("%w: index must be an integer, got '%s'", ErrInvalidIndex, index)
+	}
+
+	i := int(num)
+	if i < 0 {
+		i += len(list)
+	}
+	if i < 0 || i >= len(list) {
+		return nil, fmt.Errorf("%w: index %d of list with %d elements", ErrIndexOutOfRange, int(num), len(list))
+	}
+
+	return list[i], nil
 }
 
 func (e *Expression) String() string {
@@ -113,16 +351,48 @@ func (e *Expression) String() string {
 	if e.IsAtom() {
 		if e.IsAtomVarName() {
 			return e.GetVarName()
+		} else if e.imaginary {
+			return strconv.FormatFloat(e.value, 'f', -1, 64) + "i"
+		} else if e.unit != "" {
+			return fmt.Sprintf("(%s %s)", strconv.FormatFloat(e.value, 'f', -1, 64), e.unit)
 		} else {
 			return strconv.FormatFloat(e.value, 'f', -1, 64)
 		}
+	} else if e.IsCall() {
+		var sb strings.Builder
+		sb.WriteRune('(')
+		sb.WriteString(e.funcName)
+		for _, arg := range e.args {
+			sb.WriteRune(' ')
+			sb.WriteString(arg.String())
+		}
+		sb.WriteRune(')')
+		return sb.String()
+	} else if e.IsConditional() {
+		return fmt.Sprintf("(? %s %s %s)", e.cond, e.left, e.right)
+	} else if e.IsList() {
+		elems := make([]string, 0, len(e.args))
+		for _, elem := range e.args {
+			elems = append(elems, elem.String())
+		}
+		return "[" + strings.Join(elems, " ") + "]"
+	} else if e.IsIndex() {
+		return fmt.Sprintf("([] %s %s)", e.left, e.right)
+	} else if e.right == nil {
+		return fmt.Sprintf("(%s %s)", e.op, e.left)
 	} else {
 		return fmt.Sprintf("(%s %s %s)", e.op, e.left, e.right)
 	}
 }
 
 func NewExpressionFromLexer(lexer *Lexer) (*Expression, error) {
-	expr, err := parseExpressions(lexer, 0.0)
+	return NewExpressionFromLexerWith(lexer, JuxtapositionEqual)
+}
+
+// NewExpressionFromLexerWith creates the expression like NewExpressionFromLexer
+// with the precedence of the implicit multiplication like "2x".
+func NewExpressionFromLexerWith(lexer *Lexer, juxtaposition Juxtaposition) (*Expression, error) {
+	expr, err := parseExpressions(lexer, 0.0, juxtaposition)
 	if err != nil {
 		return nil, fmt.Errorf("failed to run NewExpressionFromLexer: %w", err)
 	}
@@ -137,13 +407,46 @@ func newAtomicVarExpression(literal string) *Expression {
 	}
 }
 
-func newAtomicNumExpression(value float64) *Expression {
+func newAtomicNumExpression(value float64, exact *big.Rat) *Expression {
+	return &Expression{
+		typ:   ExprTypeAtomic,
+		value: value,
+		exact: exact,
+	}
+}
+
+func newImaginaryExpression(value float64) *Expression {
+	return &Expression{
+		typ:       ExprTypeAtomic,
+		value:     value,
+		imaginary: true,
+	}
+}
+
+func newUnitExpression(value float64, unitName string) *Expression {
 	return &Expression{
 		typ:   ExprTypeAtomic,
 		value: value,
+		unit:  unitName,
 	}
 }
 
+// withUnitNames replaces the variables named after units with the units
+func (e *Expression) withUnitNames() *Expression {
+	if e == nil {
+		return nil
+	}
+
+	if e.IsAtomVarName() && unit.IsUnit(e.variableName) {
+		return newUnitExpression(1, e.variableName)
+	}
+	if e.IsOperation() {
+		return newOperationExpression(e.op, e.left.withUnitNames(), e.right.withUnitNames())
+	}
+
+	return e
+}
+
 func newOperationExpression(op operator.Operator, left, right *Expression) *Expression {
 	return &Expression{
 		typ:   ExprTypeOperation,
@@ -153,6 +456,52 @@ func newOperationExpression(op operator.Operator, left, right *Expression) *Expr
 	}
 }
 
+// newPostfixExpression creates an operation expression of the postfix operator
+// like "5!", the operand is the left expression and there is no right one.
+func newPostfixExpression(op operator.Operator, operand *Expression) *Expression {
+	return &Expression{
+		typ:  ExprTypeOperation,
+		op:   op,
+		left: operand,
+	}
+}
+
+func newCallExpression(funcName string, args []*Expression) *Expression {
+	return &Expression{
+		typ:      ExprTypeCall,
+		funcName: funcName,
+		args:     args,
+	}
+}
+
+// newConditionalExpression creates an expression evaluates to
+// the left expression if the condition is true, or the right one.
+func newConditionalExpression(cond, left, right *Expression) *Expression {
+	return &Expression{
+		typ:   ExprTypeConditional,
+		cond:  cond,
+		left:  left,
+		right: right,
+	}
+}
+
+func newListExpression(elems []*Expression) *Expression {
+	return &Expression{
+		typ:  ExprTypeList,
+		args: elems,
+	}
+}
+
+// newIndexExpression creates an expression evaluates to
+// the element of the list at the index like "v[0]".
+func newIndexExpression(list, index *Expression) *Expression {
+	return &Expression{
+		typ:   ExprTypeIndex,
+		left:  list,
+		right: index,
+	}
+}
+
 func (e *Expression) IsAtom() bool {
 	return e != nil && e.typ == ExprTypeAtomic
 }
@@ -161,6 +510,22 @@ func (e *Expression) IsOperation() bool {
 	return e != nil && e.typ == ExprTypeOperation
 }
 
+func (e *Expression) IsCall() bool {
+	return e != nil && e.typ == ExprTypeCall
+}
+
+func (e *Expression) IsConditional() bool {
+	return e != nil && e.typ == ExprTypeConditional
+}
+
+func (e *Expression) IsList() bool {
+	return e != nil && e.typ == ExprTypeList
+}
+
+func (e *Expression) IsIndex() bool {
+	return e != nil && e.typ == ExprTypeIndex
+}
+
 // IsAtomVarName checks if the expression is an atom variable name.
 func (e *Expression) IsAtomVarName() bool {
 	return e != nil && e.IsAtom() && e.variableName != ""
@@ -170,6 +535,32 @@ func (e *Expression) IsOPAssignment() bool {
 	return e != nil && e.IsOperation() && e.op.Is("=")
 }
 
+// IsOPCompoundAssignment checks if the expression is
+// a compound assignment like "x += 1".
+func (e *Expression) IsOPCompoundAssignment() bool {
+	if !e.IsOperation() {
+		return false
+	}
+
+	_, ok := operator.CompoundOperation(e.op)
+	return ok
+}
+
+// desugarCompoundAssignment returns the assignment of the operation
+// from a compound assignment, like "x = x + 1" from "x += 1".
+func (e *Expression) desugarCompoundAssignment() *Expression {
+	op, ok := operator.CompoundOperation(e.op)
+	if !ok {
+		// Must be a bug, don't recover it
+		panic("expression is not a compound assignment")
+	}
+
+	return newOperationExpression(
+		operator.GetOperator("="),
+		e.left,
+		newOperationExpression(op, e.left, e.right))
+}
+
 // GetVarName returns the variable name of the expression
 func (e *Expression) GetVarName() string {
 	if e.IsAtomVarName() {
@@ -207,23 +598,117 @@ func (e *Expression) GetAssignment() (string, *Expression) {
 	return "", nil
 }
 
-func (e *Expression) isNumOutOfRange(value float64) bool {
-	const effectiveBoundary = float64(1 << 53)
-	if value <= -effectiveBoundary || value >= effectiveBoundary {
+// IsFunctionDefinition checks if the expression is an assignment
+// to a function call like "f(x, y) = x * y".
+func (e *Expression) IsFunctionDefinition() bool {
+	return e.IsOPAssignment() && e.left.IsCall()
+}
+
+// getFunctionDefinition returns the user-defined function
+// from a function definition expression.
+func (e *Expression) getFunctionDefinition() (*userFunction, error) {
+	if !e.IsFunctionDefinition() {
+		// Must be a bug, don't recover it
+		panic("expression is not a function definition")
+	}
+
+	name := e.left.funcName
+	if function.IsBuiltin(name) {
+		return nil, fmt.Errorf("%w: '%s'", ErrRedefineBuiltin, name)
+	}
+
+	params := make([]string, 0, len(e.left.args))
+	for _, arg := range e.left.args {
+		param := arg.GetVarName()
+		if param == "" || param[0] == '-' || param[0] == '$' {
+			return nil, fmt.Errorf("%w: '%s' of '%s'", ErrInvalidParameter, arg, name)
+		}
+		if IsConstant(param) {
+			return nil, fmt.Errorf("%w: '%s' as parameter of '%s'", ErrAssignConstant, param, name)
+		}
+		if slices.Contains(params, param) {
+			return nil, fmt.Errorf("%w: '%s' of '%s'", ErrDuplicateParameter, param, name)
+		}
+		params = append(params, param)
+	}
+
+	if e.right == nil {
+		return nil, fmt.Errorf("missing body of function '%s'", name)
+	}
+
+	return &userFunction{
+		name:   name,
+		params: params,
+		body:   e.right,
+	}, nil
+}
+
+func isNumOutOfRange(val value.Value) bool {
+	num, ok := val.(value.Float)
+	if !ok {
+		return false
+	}
+
+	const effectiveBoundary = value.Float(1 << 53)
+	if num <= -effectiveBoundary || num >= effectiveBoundary {
 		return true
 	}
 
 	return false
 }
 
-func parseExpressions(lexer *Lexer, minBP float32) (*Expression, error) {
+// isOverflow checks if the result is infinite while the operands are finite
+// like "2 ** 1024", the infinity is only allowed from the constant 'inf'.
+// Otherwise it checks if the integer arithmetic like "2 ** 60" lost precision,
+// the result is too large/small while all the operands are exact integers.
+// The operands beyond the boundary like 6.02e23 are approximate anyway.
+func isOverflow(oprands []value.Value, result value.Value) bool {
+	if !isNumOutOfRange(result) {
+		return false
+	}
+
+	if num := result.(value.Float); math.IsInf(float64(num), 0) {
+		return !slices.ContainsFunc(oprands, isInf)
+	}
+
+	for _, oprand := range oprands {
+		num, ok := oprand.(value.Float)
+		if !ok || float64(num) != math.Trunc(float64(num)) || isNumOutOfRange(num) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isInf(val value.Value) bool {
+	num, ok := val.(value.Float)
+	return ok && math.IsInf(float64(num), 0)
+}
+
+func parseExpressions(lexer *Lexer, minBP float32, juxtaposition Juxtaposition) (*Expression, error) {
 	// parenBalance is used to track the balance of parentheses.
 	// Increment it when we encounter a left parenthesis
 	// and decrement it when we encounter a right parenthesis.
 	// If it goes below zero, we have some unmatched right parentheses.
 	parenBalance := 0
 
+	// bracketDepth is used to track how many lists or indexes we are inside of,
+	// a right bracket is only allowed to end them.
+	bracketDepth := 0
+
+	// argDepth is used to track how many function calls or lists we are inside of,
+	// a comma is only allowed to separate the arguments or the elements.
+	argDepth := 0
+
+	// condDepth is used to track how many conditional expressions we are
+	// inside the first branch of, a colon is only allowed to end the branch.
+	condDepth := 0
+
 	var parse func(*Lexer, float32) (*Expression, error)
+	var parseArguments func(*Lexer) ([]*Expression, error)
+	var parseElements func(*Lexer, string, error) ([]*Expression, error)
+	var parseConditional func(*Lexer, *Expression, float32) (*Expression, error)
 	parse = func(lexer *Lexer, minBP float32) (*Expression, error) {
 		var lhs *Expression
 		lhsToken := lexer.Next()
@@ -233,9 +718,39 @@ func parseExpressions(lexer *Lexer, minBP float32) (*Expression, error) {
 				if varName == "" {
 					return nil, fmt.Errorf("variable name is empty")
 				}
-				lhs = newAtomicVarExpression(varName)
+				if next := lexer.Peek(); next.IsOperator() && next.IsTheOperator("(") {
+					// A variable name followed by a left parenthesis is a function call
+					args, err := parseArguments(lexer)
+					if err != nil {
+						return nil, fmt.Errorf("failed to parse arguments of '%s': %w", varName, err)
+					}
+					if varName == "if" {
+						// "if(c, a, b)" is the same as "c ? a : b"
+						if len(args) != 3 {
+							return nil, fmt.Errorf(
+								"%w: must have exactly 3 arguments for 'if'",
+								function.ErrInvalidArgumentCount)
+						}
+						lhs = newConditionalExpression(args[0], args[1], args[2])
+					} else {
+						lhs = newCallExpression(varName, args)
+					}
+				} else {
+					lhs = newAtomicVarExpression(varName)
+				}
+			} else if lhsToken.IsImaginary() {
+				lhs = newImaginaryExpression(lhsToken.GetValue())
+			} else if next := lexer.Peek(); next.IsAtomVariable() && unit.IsUnit(next.GetVarName()) {
+				// A number followed by a unit like "5 km" is a quantity,
+				// or the multiplication if the unit is shadowed by a variable
+				lexer.Next()
+				lhs = newUnitExpression(lhsToken.GetValue(), next.GetVarName())
+				lhs.exact = lhsToken.GetExactValue()
+				lhs.integer = lhsToken.IsInteger()
+				lhs.juxtaposed = true
 			} else {
-				lhs = newAtomicNumExpression(lhsToken.GetValue())
+				lhs = newAtomicNumExpression(lhsToken.GetValue(), lhsToken.GetExactValue())
+				lhs.integer = lhsToken.IsInteger()
 			}
 		} else if lhsToken.IsOperator() {
 			// Handle parentheses and EOF tokens
@@ -257,6 +772,15 @@ func parseExpressions(lexer *Lexer, minBP float32) (*Expression, error) {
 				}
 			} else if lhsToken.IsTheOperator(")") {
 				parenBalance--
+			} else if lhsToken.IsTheOperator("[") {
+				// A left bracket at the beginning is a list like "[1, 2, 3]"
+				bracketDepth++
+				elems, err := parseElements(lexer, "]", ErrMissingRightBracket)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse list: %w", err)
+				}
+				bracketDepth--
+				lhs = newListExpression(elems)
 			} else if lhsToken.IsPrefixOperator() {
 				rBP, err := lhsToken.GetPrefixBindingPower()
 				if err != nil {
@@ -274,7 +798,7 @@ func parseExpressions(lexer *Lexer, minBP float32) (*Expression, error) {
 				// Add a new operation expression with the prefix operator and 0 as the left operand,
 				// the parsed expression as the right operand.
 				// This is to handle cases like "-x" as "(- 0 x) and "+x" as "(+ 0 x)".
-				lhs = newOperationExpression(lhsToken.GetOperator(), newAtomicNumExpression(0), lhs)
+				lhs = newOperationExpression(lhsToken.GetOperator(), newAtomicNumExpression(0, new(big.Rat)), lhs)
 			}
 		} else if lhsToken.IsEOF() {
 			return nil, nil
@@ -287,6 +811,19 @@ func parseExpressions(lexer *Lexer, minBP float32) (*Expression, error) {
 			op := lexer.Peek()
 			if op.IsEOF() {
 				break
+			} else if isJuxtaposed(op) {
+				// A variable or a left parenthesis right after the left-hand side
+				// like "2x", "3(a + b)" or "(a)(b)" is multiplied
+				lBP, rBP := juxtaposition.bindingPower()
+				if lBP < minBP {
+					break
+				}
+				rhs, err := parse(lexer, rBP)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse right-hand side: %w", err)
+				}
+				lhs = newOperationExpression(operator.GetOperator("*"), lhs, rhs)
+				continue
 			} else if !op.IsOperator() {
 				return nil, fmt.Errorf("invalid operator: '%s'", op)
 			} else if op.IsTheOperator(")") {
@@ -296,6 +833,43 @@ func parseExpressions(lexer *Lexer, minBP float32) (*Expression, error) {
 					return nil, ErrMissingLeftParenthesis
 				}
 				break
+			} else if op.IsTheOperator("]") {
+				// Return an error if we find a right bracket
+				// without a matching left bracket
+				if bracketDepth == 0 {
+					return nil, ErrMissingLeftBracket
+				}
+				break
+			} else if op.IsTheOperator(",") {
+				// Return an error if we find a comma
+				// outside of the arguments of a function call
+				if argDepth == 0 {
+					return nil, ErrUnexpectedComma
+				}
+				break
+			} else if op.IsTheOperator(":") {
+				// Return an error if we find a colon
+				// outside of the first branch of a conditional expression
+				if condDepth == 0 {
+					return nil, ErrUnexpectedColon
+				}
+				break
+			}
+
+			// Apply the postfix operator like "5!" to the left-hand side,
+			// "%" is the infix modulo if it's followed by an operand like "7 % -3",
+			// otherwise it's the postfix percent like "15%"
+			if op.IsPostfixOperator() && (!op.IsInfixOperator() || !startsOperand(lexer, 1)) {
+				bp, err := op.GetPostfixBindingPower()
+				if err != nil {
+					return nil, fmt.Errorf("failed to get postfix binding power: %w", err)
+				}
+				if bp < minBP {
+					break
+				}
+				lexer.Next() // Consume the operator token
+				lhs = newPostfixExpression(op.GetOperator(), lhs)
+				continue
 			}
 
 			// Stop parsing the right-hand side expression if the left-hand side
@@ -308,18 +882,127 @@ func parseExpressions(lexer *Lexer, minBP float32) (*Expression, error) {
 				break
 			}
 
-			// Parse the right-hand side
 			lexer.Next() // Consume the operator token
+
+			// Parse both branches of the conditional expression
+			if op.IsTheOperator("?") {
+				lhs, err = parseConditional(lexer, lhs, rBP)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse conditional expression: %w", err)
+				}
+				continue
+			}
+
+			// Parse the index of a list like "v[0]"
+			if op.IsTheOperator("[") {
+				bracketDepth++
+				index, err := parse(lexer, 0.0)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse index: %w", err)
+				}
+				if index == nil {
+					return nil, ErrMissingIndex
+				}
+				if next := lexer.Next(); !next.IsOperator() || !next.IsTheOperator("]") {
+					return nil, ErrMissingRightBracket
+				}
+				bracketDepth--
+				lhs = newIndexExpression(lhs, index)
+				continue
+			}
+
+			// Parse the right-hand side
 			rhs, err := parse(lexer, rBP)
 			if err != nil {
 				return nil, fmt.Errorf("failed to parse right-hand side: %w", err)
 			}
+			if op.IsTheOperator("in") || op.IsTheOperator("to") {
+				// The names of the target like "km/h" are units even if
+				// there are variables with the same names
+				rhs = rhs.withUnitNames()
+			}
 			lhs = newOperationExpression(op.GetOperator(), lhs, rhs)
 		}
 
 		return lhs, nil
 	}
 
+	// parseArguments parses the comma-separated arguments of a function call
+	// from the left parenthesis to the matching right parenthesis.
+	parseArguments = func(lexer *Lexer) ([]*Expression, error) {
+		lexer.Next() // Consume the left parenthesis token
+		parenBalance++
+		args, err := parseElements(lexer, ")", ErrMissingRightParenthesis)
+		if err != nil {
+			return nil, err
+		}
+		parenBalance--
+
+		return args, nil
+	}
+
+	// parseElements parses the comma-separated expressions until the closing
+	// operator, the opening operator must be consumed by the caller.
+	parseElements = func(lexer *Lexer, closing string, errMissingClosing error) ([]*Expression, error) {
+		argDepth++
+		defer func() { argDepth-- }()
+
+		elems := make([]*Expression, 0)
+		if next := lexer.Peek(); next.IsOperator() && next.IsTheOperator(closing) {
+			lexer.Next()
+			return elems, nil
+		}
+
+		for {
+			elem, err := parse(lexer, 0.0)
+			if err != nil {
+				return nil, err
+			}
+			if elem == nil {
+				return nil, fmt.Errorf("%w: at position %d", ErrMissingArgument, len(elems)+1)
+			}
+			elems = append(elems, elem)
+
+			next := lexer.Next()
+			if next.IsOperator() && next.IsTheOperator(",") {
+				continue
+			}
+			if next.IsOperator() && next.IsTheOperator(closing) {
+				return elems, nil
+			}
+			return nil, errMissingClosing
+		}
+	}
+
+	// parseConditional parses the branches of a conditional expression
+	// after the question mark, the first branch is ended by the colon and
+	// the second branch is parsed with the right binding power of "?".
+	parseConditional = func(lexer *Lexer, cond *Expression, rBP float32) (*Expression, error) {
+		condDepth++
+		left, err := parse(lexer, 0.0)
+		condDepth--
+		if err != nil {
+			return nil, err
+		}
+		if left == nil {
+			return nil, ErrMissingBranch
+		}
+
+		if next := lexer.Next(); !next.IsOperator() || !next.IsTheOperator(":") {
+			return nil, ErrMissingColon
+		}
+
+		right, err := parse(lexer, rBP)
+		if err != nil {
+			return nil, err
+		}
+		if right == nil {
+			return nil, ErrMissingBranch
+		}
+
+		return newConditionalExpression(cond, left, right), nil
+	}
+
 	expr, err := parse(lexer, minBP)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse expression: %w", err)
@@ -332,3 +1015,30 @@ func parseExpressions(lexer *Lexer, minBP float32) (*Expression, error) {
 
 	return expr, nil
 }
+
+// isJuxtaposed checks if the token right after an operand starts another one
+// to multiply, it must be a variable or a left parenthesis, so "2 3" is still
+// an error. A variable followed by a left parenthesis is a function call
+// and a number followed by a unit is a quantity or the multiplication
+// decided by the variables in the evaluation before this.
+func isJuxtaposed(token Token) bool {
+	return token.IsAtomVariable() || (token.IsOperator() && token.IsTheOperator("("))
+}
+
+// startsOperand checks if the nth token after the next one starts an operand,
+// like a number, a variable, a left parenthesis or a prefix operator
+// followed by an operand, so "-" in "7 % -3" starts an operand but not in "15% )".
+func startsOperand(lexer *Lexer, n int) bool {
+	token := lexer.PeekNth(n)
+	if token.IsAtom() {
+		return true
+	}
+	if !token.IsOperator() {
+		return false
+	}
+	if token.IsTheOperator("(") || token.IsTheOperator("[") {
+		return true
+	}
+
+	return token.IsPrefixOperator() && startsOperand(lexer, n+1)
+}
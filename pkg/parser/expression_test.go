@@ -6,7 +6,9 @@ import (
 	"testing"
 
 	"simplecalc/pkg/parser"
+	"simplecalc/pkg/parser/function"
 	"simplecalc/pkg/parser/operator"
+	"simplecalc/pkg/parser/value"
 )
 
 func TestNewExpressionFromLexer(t *testing.T) {
@@ -202,6 +204,264 @@ func TestNewExpressionFromLexer(t *testing.T) {
 			want:    "(/ (- 0 (** (** (* 2.5 x) 6) y)) (** 0.5 3))",
 			wantErr: nil,
 		},
+		{
+			name:    "comparison and logical operators precedence",
+			input:   "x > 3 && y <= 2 || !z == 1",
+			want:    "(|| (&& (> x 3) (<= y 2)) (== (! 0 z) 1))",
+			wantErr: nil,
+		},
+		{
+			name:    "equality binds looser than relational operators",
+			input:   "a < b == c >= d",
+			want:    "(== (< a b) (>= c d))",
+			wantErr: nil,
+		},
+		{
+			name:    "chained assignment is right associative",
+			input:   "a = b = c += 1",
+			want:    "(= a (= b (+= c 1)))",
+			wantErr: nil,
+		},
+		{
+			name:    "nested assignment",
+			input:   "y = (x = 2) * x",
+			want:    "(= y (* (= x 2) x))",
+			wantErr: nil,
+		},
+		{
+			name:    "assignment of comparison",
+			input:   "b = 1 + 2 != 3",
+			want:    "(= b (!= (+ 1 2) 3))",
+			wantErr: nil,
+		},
+		{
+			name:    "conditional expression",
+			input:   "x == 0 ? 0 : 1 / x",
+			want:    "(? (== x 0) 0 (/ 1 x))",
+			wantErr: nil,
+		},
+		{
+			name:    "conditional expression is right associative",
+			input:   "a ? b : c ? d : e",
+			want:    "(? a b (? c d e))",
+			wantErr: nil,
+		},
+		{
+			name:    "nested conditional expression in the first branch",
+			input:   "a ? b ? c : d : e",
+			want:    "(? a (? b c d) e)",
+			wantErr: nil,
+		},
+		{
+			name:    "conditional expression binds looser than logical operators",
+			input:   "y = a || b ? 1 + 2 : 3",
+			want:    "(= y (? (|| a b) (+ 1 2) 3))",
+			wantErr: nil,
+		},
+		{
+			name:    "if function is a conditional expression",
+			input:   "if(x > 0, x, -x)",
+			want:    "(? (> x 0) x (- 0 x))",
+			wantErr: nil,
+		},
+		{
+			name:    "if function with wrong argument count",
+			input:   "if(x > 0, x)",
+			want:    "",
+			wantErr: function.ErrInvalidArgumentCount,
+		},
+		{
+			name:    "conditional expression missing colon",
+			input:   "x ? 1",
+			want:    "",
+			wantErr: parser.ErrMissingColon,
+		},
+		{
+			name:    "conditional expression missing second branch",
+			input:   "x ? 1 :",
+			want:    "",
+			wantErr: parser.ErrMissingBranch,
+		},
+		{
+			name:    "colon outside of conditional expression",
+			input:   "1 : 2",
+			want:    "",
+			wantErr: parser.ErrUnexpectedColon,
+		},
+		{
+			name:    "bitwise operators follow C precedence",
+			input:   "a & b ^ c | d && e",
+			want:    "(&& (| (^ (& a b) c) d) e)",
+			wantErr: nil,
+		},
+		{
+			name:    "shift binds looser than arithmetic and tighter than comparison",
+			input:   "1 << n + 1 < m >> 2",
+			want:    "(< (<< 1 (+ n 1)) (>> m 2))",
+			wantErr: nil,
+		},
+		{
+			name:    "bitwise and binds tighter than equality",
+			input:   "flags & mask == 0",
+			want:    "(& flags (== mask 0))",
+			wantErr: nil,
+		},
+		{
+			name:    "bitwise not",
+			input:   "~x & 0",
+			want:    "(& (~ 0 x) 0)",
+			wantErr: nil,
+		},
+		{
+			name:    "bitwise xor and power",
+			input:   "2 ** 3 ^ 1",
+			want:    "(^ (** 2 3) 1)",
+			wantErr: nil,
+		},
+		{
+			name:    "function call",
+			input:   "sqrt(2)",
+			want:    "(sqrt 2)",
+			wantErr: nil,
+		},
+		{
+			name:    "function call with multiple arguments",
+			input:   "log(8, 2) + 1",
+			want:    "(+ (log 8 2) 1)",
+			wantErr: nil,
+		},
+		{
+			name:    "function call without arguments",
+			input:   "f()",
+			want:    "(f)",
+			wantErr: nil,
+		},
+		{
+			name:    "nested function calls with operations",
+			input:   "-max(abs(-3), (2 + 1) * 4) ** 2",
+			want:    "(- 0 (** (max (abs (- 0 3)) (* (+ 2 1) 4)) 2))",
+			wantErr: nil,
+		},
+		{
+			name:    "function call missing right parenthesis",
+			input:   "sqrt(2",
+			want:    "",
+			wantErr: parser.ErrMissingRightParenthesis,
+		},
+		{
+			name:    "function call with trailing comma",
+			input:   "min(1, )",
+			want:    "",
+			wantErr: parser.ErrMissingArgument,
+		},
+		{
+			name:    "comma outside of function call",
+			input:   "1, 2",
+			want:    "",
+			wantErr: parser.ErrUnexpectedComma,
+		},
+		{
+			name:    "list",
+			input:   "[1, x + 2, [3]]",
+			want:    "[1 (+ x 2) [3]]",
+			wantErr: nil,
+		},
+		{
+			name:    "empty list",
+			input:   "[]",
+			want:    "[]",
+			wantErr: nil,
+		},
+		{
+			name:    "index binds tighter than prefix and power",
+			input:   "-v[0] ** m[1][i + 1]",
+			want:    "(- 0 (** ([] v 0) ([] ([] m 1) (+ i 1))))",
+			wantErr: nil,
+		},
+		{
+			name:    "factorial binds tighter than power and prefix minus",
+			input:   "-2 ** 3! + 3! ** 2",
+			want:    "(+ (- 0 (** 2 (! 3))) (** (! 3) 2))",
+			wantErr: nil,
+		},
+		{
+			name:    "factorial after prefix not",
+			input:   "!x!",
+			want:    "(! 0 (! x))",
+			wantErr: nil,
+		},
+		{
+			name:    "percent at the end",
+			input:   "200 * 15%",
+			want:    "(* 200 (% 15))",
+			wantErr: nil,
+		},
+		{
+			name:    "percent before operator",
+			input:   "(15% * 200)",
+			want:    "(* (% 15) 200)",
+			wantErr: nil,
+		},
+		{
+			name:    "percent sign followed by operand is modulo",
+			input:   "7 % x % (2) % ~1",
+			want:    "(% (% (% 7 x) 2) (~ 0 1))",
+			wantErr: nil,
+		},
+		{
+			name:    "percent sign followed by unary minus is modulo",
+			input:   "15% - 5",
+			want:    "(% 15 (- 0 5))",
+			wantErr: nil,
+		},
+		{
+			name:    "percent sign followed by right parenthesis is percent",
+			input:   "(15%) - 5",
+			want:    "(- (% 15) 5)",
+			wantErr: nil,
+		},
+		{
+			name:    "implicit multiplication",
+			input:   "2x + 3(a + b) - (a)(b)",
+			want:    "(- (+ (* 2 x) (* 3 (+ a b))) (* a b))",
+			wantErr: nil,
+		},
+		{
+			name:    "implicit multiplication as tight as multiply",
+			input:   "1 / 2x ** 2",
+			want:    "(* (/ 1 2) (** x 2))",
+			wantErr: nil,
+		},
+		{
+			name:    "digits in variable name are not multiplied",
+			input:   "2x2 y",
+			want:    "(* (* 2 x2) y)",
+			wantErr: nil,
+		},
+		{
+			name:    "variable followed by parenthesis is function call",
+			input:   "2f(x)(y)",
+			want:    "(* (* 2 (f x)) y)",
+			wantErr: nil,
+		},
+		{
+			name:    "list missing right bracket",
+			input:   "[1, 2",
+			want:    "",
+			wantErr: parser.ErrMissingRightBracket,
+		},
+		{
+			name:    "right bracket without left bracket",
+			input:   "1 + 2]",
+			want:    "",
+			wantErr: parser.ErrMissingLeftBracket,
+		},
+		{
+			name:    "missing index",
+			input:   "v[]",
+			want:    "",
+			wantErr: parser.ErrMissingIndex,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -228,6 +488,50 @@ func TestNewExpressionFromLexer(t *testing.T) {
 	}
 }
 
+func TestNewExpressionFromLexerWith_Juxtaposition(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		juxtaposition parser.Juxtaposition
+		want          string
+	}{
+		{
+			name:          "equal to division",
+			input:         "1 / 2x / y",
+			juxtaposition: parser.JuxtapositionEqual,
+			want:          "(/ (* (/ 1 2) x) y)",
+		},
+		{
+			name:          "tighter than division",
+			input:         "1 / 2x / y",
+			juxtaposition: parser.JuxtapositionTight,
+			want:          "(/ (/ 1 (* 2 x)) y)",
+		},
+		{
+			name:          "looser than power and prefix minus",
+			input:         "-2x ** 2",
+			juxtaposition: parser.JuxtapositionTight,
+			want:          "(* (- 0 2) (** x 2))",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lexer, err := parser.NewLexer(tt.input)
+			if err != nil {
+				t.Fatalf("NewLexer() error = %v", err)
+			}
+
+			expr, err := parser.NewExpressionFromLexerWith(lexer, tt.juxtaposition)
+			if err != nil {
+				t.Fatalf("NewExpressionFromLexerWith() error = %v", err)
+			}
+			if got := expr.String(); got != tt.want {
+				t.Errorf("Expression.String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestExpressionEvaluate(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -382,10 +686,35 @@ func TestExpressionEvaluate(t *testing.T) {
 		{
 			name:      "support negative variable assignment",
 			input:     "x = -y + 2",
-			want:      0,
+			want:      -10,
 			wantErr:   nil,
 			variables: map[string]float64{"x": 7, "y": 12},
 		},
+		{
+			name:      "nested assignment is evaluated before the rest",
+			input:     "(y = x + 1) * y",
+			want:      9,
+			wantErr:   nil,
+			variables: map[string]float64{"x": 2},
+		},
+		{
+			name:    "assignment to constant",
+			input:   "1 + (pi = 3)",
+			want:    0,
+			wantErr: parser.ErrAssignConstant,
+		},
+		{
+			name:    "assignment to number",
+			input:   "x = 2 = 1",
+			want:    0,
+			wantErr: parser.ErrInvalidAssignment,
+		},
+		{
+			name:    "nested compound assignment to undefined variable",
+			input:   "1 + (x += 1)",
+			want:    0,
+			wantErr: parser.ErrUndefinedVariable,
+		},
 		{
 			// 1<<53 is 9007199254740992
 			name:    "too large number",
@@ -400,6 +729,12 @@ func TestExpressionEvaluate(t *testing.T) {
 			want:    0,
 			wantErr: parser.ErrNumOutOfRange,
 		},
+		{
+			name:    "too large hexadecimal literal",
+			input:   "0x10000000000000000000",
+			want:    0,
+			wantErr: parser.ErrNumOutOfRange,
+		},
 		{
 			name:    "too large number from operation",
 			input:   "9007199254740991 + 1",
@@ -413,11 +748,37 @@ func TestExpressionEvaluate(t *testing.T) {
 			wantErr: parser.ErrNumOutOfRange,
 		},
 		{
-			name:      "too large number from variable",
-			input:     "x",
-			want:      0,
-			wantErr:   parser.ErrNumOutOfRange,
-			variables: map[string]float64{"x": 9007199254740992},
+			name:    "infinite number from operation",
+			input:   "2 ** 1024",
+			want:    0,
+			wantErr: parser.ErrNumOutOfRange,
+		},
+		{
+			name:    "infinite number from operation on approximate numbers",
+			input:   "1.5 ** 2000 + 1e308 * 10",
+			want:    0,
+			wantErr: parser.ErrNumOutOfRange,
+		},
+		{
+			name:    "infinite number from function",
+			input:   "exp(1000)",
+			want:    0,
+			wantErr: parser.ErrNumOutOfRange,
+		},
+		{
+			name:    "operation on infinity constant",
+			input:   "inf * 2 + 1",
+			want:    math.Inf(1),
+			wantErr: nil,
+		},
+		{
+			// Only the integer arithmetic is checked, the variable
+			// beyond the boundary is an approximate number
+			name:      "large number from variable",
+			input:     "x * 2",
+			want:      1.204e24,
+			wantErr:   nil,
+			variables: map[string]float64{"x": 6.02e23},
 		},
 		{
 			name:      "too large number from variable with operation",
@@ -469,6 +830,361 @@ func TestExpressionEvaluate(t *testing.T) {
 			want:    0,
 			wantErr: operator.ErrInvalidOperandCount,
 		},
+		{
+			name:    "modulo with floor semantics",
+			input:   "-7 % 3",
+			want:    2,
+			wantErr: nil,
+		},
+		{
+			name:    "modulo with negative divisor",
+			input:   "7 % -3",
+			want:    -2,
+			wantErr: nil,
+		},
+		{
+			name:    "floor division",
+			input:   "-7 // 2",
+			want:    -4,
+			wantErr: nil,
+		},
+		{
+			name:    "remainder with truncated semantics",
+			input:   "-7 rem 3",
+			want:    -1,
+			wantErr: nil,
+		},
+		{
+			name:    "factorial",
+			input:   "5! + 0!",
+			want:    121,
+			wantErr: nil,
+		},
+		{
+			name:    "factorial of exponent",
+			input:   "2 ** 3!",
+			want:    64,
+			wantErr: nil,
+		},
+		{
+			name:    "factorial before unary minus",
+			input:   "-3!",
+			want:    -6,
+			wantErr: nil,
+		},
+		{
+			name:    "factorial of non-integer is gamma function",
+			input:   "0.5!",
+			want:    math.Sqrt(math.Pi) / 2,
+			wantErr: nil,
+		},
+		{
+			name:    "factorial of negative integer",
+			input:   "(-3)!",
+			want:    0,
+			wantErr: operator.ErrInvalidOperand,
+		},
+		{
+			name:    "percent",
+			input:   "200 * 15%",
+			want:    30,
+			wantErr: nil,
+		},
+		{
+			name:    "percent after addition",
+			input:   "1 + 200 * 15%",
+			want:    31,
+			wantErr: nil,
+		},
+		{
+			name:    "percent sign before unary minus is modulo",
+			input:   "15% - 5",
+			want:    0,
+			wantErr: nil,
+		},
+		{
+			name:    "percent in parentheses before subtraction",
+			input:   "(15%) - 5",
+			want:    -4.85,
+			wantErr: nil,
+		},
+		{
+			name:      "percent of variable before power",
+			input:     "x% ** 2",
+			want:      0.25,
+			wantErr:   nil,
+			variables: map[string]float64{"x": 50},
+		},
+		{
+			name:    "modulo has the same binding power as multiply",
+			input:   "1 + 10 % 4 * 3",
+			want:    7,
+			wantErr: nil,
+		},
+		{
+			name:    "modulo by zero",
+			input:   "1 % 0",
+			want:    0,
+			wantErr: operator.ErrDivisionByZero,
+		},
+		{
+			name:    "floor division by zero",
+			input:   "1 // 0",
+			want:    0,
+			wantErr: operator.ErrDivisionByZero,
+		},
+		{
+			name:    "remainder by zero",
+			input:   "1 rem 0",
+			want:    0,
+			wantErr: operator.ErrDivisionByZero,
+		},
+		{
+			name:      "conditional expression evaluates only the selected branch",
+			input:     "x == 0 ? 0 : 1 / x",
+			want:      0,
+			wantErr:   nil,
+			variables: map[string]float64{"x": 0},
+		},
+		{
+			name:      "conditional expression selects the second branch",
+			input:     "x == 0 ? 0 : 1 / x",
+			want:      0.25,
+			wantErr:   nil,
+			variables: map[string]float64{"x": 4},
+		},
+		{
+			name:      "chained conditional expressions",
+			input:     "x < 10 ? 1 : x < 100 ? 2 : 3",
+			want:      2,
+			wantErr:   nil,
+			variables: map[string]float64{"x": 42},
+		},
+		{
+			name:      "if function evaluates only the selected branch",
+			input:     "if(x != 0, 1 / x, 0)",
+			want:      0,
+			wantErr:   nil,
+			variables: map[string]float64{"x": 0},
+		},
+		{
+			name:    "bitwise and, or, xor",
+			input:   "(12 & 10) | (6 ^ 3)",
+			want:    13,
+			wantErr: nil,
+		},
+		{
+			name:    "shift operators",
+			input:   "(1 << 10) + (-256 >> 4)",
+			want:    1008,
+			wantErr: nil,
+		},
+		{
+			name:    "bitwise not",
+			input:   "~5",
+			want:    -6,
+			wantErr: nil,
+		},
+		{
+			name:    "bitwise operator with non-integral operand",
+			input:   "1.5 & 1",
+			want:    0,
+			wantErr: operator.ErrInvalidOperand,
+		},
+		{
+			name:    "bitwise not with non-integral operand",
+			input:   "~0.5",
+			want:    0,
+			wantErr: operator.ErrInvalidOperand,
+		},
+		{
+			name:    "shift with negative count",
+			input:   "1 << -1",
+			want:    0,
+			wantErr: operator.ErrInvalidOperand,
+		},
+		{
+			name:    "shift beyond float64 integers",
+			input:   "1 << 63",
+			want:    0,
+			wantErr: operator.ErrInvalidOperand,
+		},
+		{
+			name:    "hexadecimal, binary and octal literals",
+			input:   "0xFF + 0b1010 - 0o755",
+			want:    255 + 10 - 493,
+			wantErr: nil,
+		},
+		{
+			name:    "digit separators",
+			input:   "1_000_000 + 0.000_5",
+			want:    1000000.0005,
+			wantErr: nil,
+		},
+		{
+			name:    "scientific notation",
+			input:   "1.5E-9 * 2e3",
+			want:    3e-6,
+			wantErr: nil,
+		},
+		{
+			name:    "scientific notation with positive exponent sign",
+			input:   "3e+8 / 1e8",
+			want:    3,
+			wantErr: nil,
+		},
+		{
+			name:    "scientific notation beyond float64 integers",
+			input:   "6.02e23",
+			want:    6.02e23,
+			wantErr: nil,
+		},
+		{
+			name:    "operation of scientific notation",
+			input:   "6.02e23 / 1e20",
+			want:    6020,
+			wantErr: nil,
+		},
+		{
+			name:    "too large number from integer function",
+			input:   "sum(9007199254740991, 1)",
+			want:    0,
+			wantErr: parser.ErrNumOutOfRange,
+		},
+		{
+			name:    "square root function",
+			input:   "sqrt(16) + 1",
+			want:    5,
+			wantErr: nil,
+		},
+		{
+			name:    "logarithm with base",
+			input:   "log(1024, 2)",
+			want:    10,
+			wantErr: nil,
+		},
+		{
+			name:      "function with variable arguments",
+			input:     "hypot(x, y) * 2",
+			want:      10,
+			wantErr:   nil,
+			variables: map[string]float64{"x": 3, "y": 4},
+		},
+		{
+			name:    "trigonometric function",
+			input:   "atan2(1, 1) * 4",
+			want:    math.Pi,
+			wantErr: nil,
+		},
+		{
+			name:    "wrong argument count",
+			input:   "sqrt(1, 2)",
+			want:    0,
+			wantErr: function.ErrInvalidArgumentCount,
+		},
+		{
+			name:    "undefined function",
+			input:   "foo(1)",
+			want:    0,
+			wantErr: function.ErrFunctionNotFound,
+		},
+		{
+			name:    "argument out of domain",
+			input:   "ln(0)",
+			want:    0,
+			wantErr: function.ErrOutOfDomain,
+		},
+		{
+			name:    "mean of arguments",
+			input:   "mean(1, 4, 9)",
+			want:    14.0 / 3,
+			wantErr: nil,
+		},
+		{
+			name:    "median of odd count",
+			input:   "median(7, 1, 3)",
+			want:    3,
+			wantErr: nil,
+		},
+		{
+			name:    "median of even count",
+			input:   "median(4, 1, 3, 2)",
+			want:    2.5,
+			wantErr: nil,
+		},
+		{
+			name:    "sample variance",
+			input:   "variance(2, 4, 4, 4, 5, 5, 7, 9)",
+			want:    32.0 / 7,
+			wantErr: nil,
+		},
+		{
+			name:    "variance of large numbers close to each other",
+			input:   "variance(1e9 + 4, 1e9 + 7, 1e9 + 13, 1e9 + 16)",
+			want:    30,
+			wantErr: nil,
+		},
+		{
+			name:    "sample standard deviation",
+			input:   "stdev(1, 2, 3, 4)",
+			want:    math.Sqrt(5.0 / 3),
+			wantErr: nil,
+		},
+		{
+			name:    "percentile interpolates between ranks",
+			input:   "percentile(90, 1, 2, 3, 4)",
+			want:    3.7,
+			wantErr: nil,
+		},
+		{
+			name:    "percentile of lists",
+			input:   "percentile(100, [1, 5], [3])",
+			want:    5,
+			wantErr: nil,
+		},
+		{
+			name:    "mode with ties is the smallest",
+			input:   "mode(3, 1, 3, 2, 1)",
+			want:    1,
+			wantErr: nil,
+		},
+		{
+			name:    "minimum and maximum of lists",
+			input:   "min([3, 1], 2) + max(1, [7, 2])",
+			want:    8,
+			wantErr: nil,
+		},
+		{
+			name:      "sum and product of variables",
+			input:     "sum(x, y, 3) * product(x, y, 3)",
+			want:      (2 + 5 + 3) * (2 * 5 * 3),
+			wantErr:   nil,
+			variables: map[string]float64{"x": 2, "y": 5},
+		},
+		{
+			name:    "variance of single number",
+			input:   "variance(1)",
+			want:    0,
+			wantErr: function.ErrOutOfDomain,
+		},
+		{
+			name:    "percentile out of range",
+			input:   "percentile(101, 1, 2)",
+			want:    0,
+			wantErr: function.ErrOutOfDomain,
+		},
+		{
+			name:    "median of empty list",
+			input:   "median([])",
+			want:    0,
+			wantErr: function.ErrOutOfDomain,
+		},
+		{
+			name:    "statistics function without arguments",
+			input:   "stdev()",
+			want:    0,
+			wantErr: function.ErrInvalidArgumentCount,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -484,16 +1200,21 @@ func TestExpressionEvaluate(t *testing.T) {
 				return
 			}
 
-			if tt.variables == nil {
-				tt.variables = make(map[string]float64)
+			variables := make(map[string]value.Value, len(tt.variables))
+			for name, num := range tt.variables {
+				variables[name] = value.Float(num)
 			}
-			got, err := expr.Evaluate(tt.variables)
+			val, err := expr.Evaluate(variables)
 			if !errors.Is(err, tt.wantErr) {
 				t.Fatalf("Evaluate() error = %v, wantErr %v", err, tt.wantErr)
 			}
 			if tt.wantErr != nil {
 				return
 			}
+			got, err := value.ToFloat(val)
+			if err != nil {
+				t.Fatalf("Evaluate() = %v, want a number", val)
+			}
 			if math.Abs(got-tt.want) > parser.IntApproxTolerance {
 				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
 			}
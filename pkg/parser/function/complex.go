@@ -0,0 +1,42 @@
+package function
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+func init() {
+	registerFunction(withComplex(unary("re", wrap(func(x float64) float64 {
+		return x
+	})), complexUnary(func(x complex128) complex128 {
+		return complex(real(x), 0)
+	})))
+	registerFunction(withComplex(unary("im", wrap(func(x float64) float64 {
+		return 0
+	})), complexUnary(func(x complex128) complex128 {
+		return complex(imag(x), 0)
+	})))
+	registerFunction(withComplex(unary("conj", wrap(func(x float64) float64 {
+		return x
+	})), complexUnary(cmplx.Conj)))
+	registerFunction(withComplex(unary("arg", wrap(func(x float64) float64 {
+		// The phase of a negative number is pi
+		return math.Atan2(0, x)
+	})), complexUnary(func(x complex128) complex128 {
+		return complex(cmplx.Phase(x), 0)
+	})))
+}
+
+// withComplex sets the implementation of the function for complex arguments
+func withComplex(fn *Function, complex func(args []complex128) (complex128, error)) *Function {
+	fn.complex = complex
+	return fn
+}
+
+// complexUnary adapts a function from the math/cmplx package
+// which accepts exactly one argument and never fails
+func complexUnary(fn func(complex128) complex128) func(args []complex128) (complex128, error) {
+	return func(args []complex128) (complex128, error) {
+		return fn(args[0]), nil
+	}
+}
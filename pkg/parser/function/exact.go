@@ -0,0 +1,79 @@
+package function
+
+import (
+	"math/big"
+
+	"simplecalc/pkg/parser/value"
+)
+
+// withExact sets the implementation of the function for exact arguments
+func withExact(fn *Function, exact func(args []value.Value) (value.Value, error)) *Function {
+	fn.exact = exact
+	return fn
+}
+
+// exactUnary adapts an exact function that accepts exactly one argument
+func exactUnary(fn func(value.Value) (value.Value, error)) func(args []value.Value) (value.Value, error) {
+	return func(args []value.Value) (value.Value, error) {
+		return fn(args[0])
+	}
+}
+
+// exactSqrt keeps the square root of a perfect square rational number exact
+func exactSqrt(v value.Value) (value.Value, error) {
+	digits := value.Digits(v)
+	sign, err := value.Compare(v, value.NewRational(new(big.Rat), digits))
+	if err != nil {
+		return nil, err
+	}
+	if sign < 0 {
+		// The square root of a negative number is imaginary
+		return nil, errComplex
+	}
+
+	if r, ok := v.(value.Rational); ok {
+		num := new(big.Int).Sqrt(r.Rat().Num())
+		denom := new(big.Int).Sqrt(r.Rat().Denom())
+		root := new(big.Rat).SetFrac(num, denom)
+		if new(big.Rat).Mul(root, root).Cmp(r.Rat()) == 0 {
+			return value.NewRational(root, digits), nil
+		}
+	}
+
+	x, err := value.ToBigFloat(v, digits)
+	if err != nil {
+		return nil, err
+	}
+
+	return value.NewBigFloat(x.Sqrt(x), digits), nil
+}
+
+func exactAbs(v value.Value) (value.Value, error) {
+	sign, err := value.Compare(v, value.NewRational(new(big.Rat), value.Digits(v)))
+	if err != nil {
+		return nil, err
+	}
+	if sign < 0 {
+		return value.Negate(v)
+	}
+
+	return v, nil
+}
+
+// exactExtremum returns the argument that compares to all others
+// with the sign, -1 for the minimum and 1 for the maximum.
+func exactExtremum(sign int) func(args []value.Value) (value.Value, error) {
+	return func(args []value.Value) (value.Value, error) {
+		result := args[0]
+		for _, arg := range args[1:] {
+			c, err := value.Compare(arg, result)
+			if err != nil {
+				return nil, err
+			}
+			if c == sign {
+				result = arg
+			}
+		}
+		return result, nil
+	}
+}
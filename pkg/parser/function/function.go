@@ -0,0 +1,181 @@
+package function
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"simplecalc/pkg/parser/value"
+)
+
+var (
+	ErrFunctionNotFound     = fmt.Errorf("function not found")
+	ErrInvalidArgumentCount = fmt.Errorf("invalid argument count")
+	ErrOutOfDomain          = fmt.Errorf("argument out of domain")
+)
+
+// errComplex is returned from the real implementation of a function
+// if the result is complex, then the complex implementation is used.
+var errComplex = errors.New("complex result")
+
+// Variadic is used as maxArgs for functions that accept any number of arguments
+const Variadic = -1
+
+type Function struct {
+	name    string
+	minArgs int
+	maxArgs int
+	eval    func(args []float64) (float64, error)
+	// exact is optional, it's called if any of the arguments is exact
+	exact func(args []value.Value) (value.Value, error)
+	// complex is optional, it's called if any of the arguments is complex
+	complex func(args []complex128) (complex128, error)
+	// values replaces all others for the functions accepting lists,
+	// it's called with the arguments as they are.
+	values func(args []value.Value) (value.Value, error)
+	// series is set for the functions of a series of numbers like min,
+	// the elements of the list arguments are passed as the arguments.
+	series bool
+}
+
+// set container that registers all built-in functions from init
+var allFunctions = map[string]*Function{}
+
+func registerFunction(fn *Function) {
+	if fn.name == "" {
+		panic("function name cannot be empty")
+	}
+	if _, ok := allFunctions[fn.name]; ok {
+		panic(fmt.Sprintf("function '%s' already registered", fn.name))
+	}
+	allFunctions[fn.name] = fn
+}
+
+// GetFunction returns the built-in function registered with the name
+func GetFunction(name string) (*Function, error) {
+	if fn, ok := allFunctions[name]; ok {
+		return fn, nil
+	}
+
+	return nil, fmt.Errorf("%w: '%s'", ErrFunctionNotFound, name)
+}
+
+// IsBuiltin checks if the name is registered as a built-in function
+func IsBuiltin(name string) bool {
+	_, ok := allFunctions[name]
+	return ok
+}
+
+func (f *Function) GetName() string {
+	return f.name
+}
+
+// Evaluate checks the arity of the function and calls it with the arguments,
+// the result of a function without exact implementation is only as precise
+// as float64 even if the arguments are exact.
+func (f *Function) Evaluate(args []value.Value) (value.Value, error) {
+	if len(args) < f.minArgs || (f.maxArgs != Variadic && len(args) > f.maxArgs) {
+		return nil, fmt.Errorf("%w: %s for '%s' function", ErrInvalidArgumentCount, f.arity(), f.name)
+	}
+
+	if f.series {
+		args = flatten(args)
+		if len(args) < f.minArgs {
+			return nil, fmt.Errorf("%w: '%s' of empty list", ErrOutOfDomain, f.name)
+		}
+	}
+
+	if f.values != nil {
+		return f.values(args)
+	}
+
+	if f.complex != nil && slices.ContainsFunc(args, isComplex) {
+		return f.evaluateComplex(args)
+	}
+
+	var digits uint
+	nums := make([]float64, 0, len(args))
+	for i, arg := range args {
+		num, err := value.ToFloat(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument %d of '%s': %w", i+1, f.name, err)
+		}
+		nums = append(nums, num)
+		digits = max(digits, value.Digits(arg))
+	}
+
+	if digits > 0 && f.exact != nil {
+		result, err := f.exact(args)
+		if errors.Is(err, errComplex) {
+			return f.evaluateComplex(args)
+		}
+		return result, err
+	}
+
+	result, err := f.eval(nums)
+	if errors.Is(err, errComplex) {
+		return f.evaluateComplex(args)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if digits > 0 {
+		if math.IsNaN(result) {
+			return nil, fmt.Errorf("'%s' function: %w", f.name, value.ErrNaN)
+		}
+		return value.NewBigFloatFromFloat(result, digits), nil
+	}
+
+	return value.Float(result), nil
+}
+
+// evaluateComplex calls the complex implementation with the arguments,
+// the result is a real number if its imaginary part is zero.
+func (f *Function) evaluateComplex(args []value.Value) (value.Value, error) {
+	nums := make([]complex128, 0, len(args))
+	for i, arg := range args {
+		num, err := value.ToComplex(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument %d of '%s': %w", i+1, f.name, err)
+		}
+		nums = append(nums, num)
+	}
+
+	result, err := f.complex(nums)
+	if err != nil {
+		return nil, err
+	}
+
+	return value.FromComplex(result), nil
+}
+
+func isComplex(v value.Value) bool {
+	return v.Kind() == value.KindComplex
+}
+
+func (f *Function) arity() string {
+	switch {
+	case f.maxArgs == Variadic:
+		return fmt.Sprintf("must have at least %d arguments", f.minArgs)
+	case f.minArgs == f.maxArgs:
+		return fmt.Sprintf("must have exactly %d arguments", f.minArgs)
+	default:
+		return fmt.Sprintf("must have %d to %d arguments", f.minArgs, f.maxArgs)
+	}
+}
+
+func (f *Function) String() string {
+	return f.name
+}
+
+// Names returns the sorted names of all built-in functions
+func Names() []string {
+	names := make([]string, 0, len(allFunctions))
+	for name := range allFunctions {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
@@ -0,0 +1,304 @@
+package function_test
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+	"testing"
+
+	fn "simplecalc/pkg/parser/function"
+	"simplecalc/pkg/parser/value"
+)
+
+func TestFunction_Evaluate(t *testing.T) {
+	tests := []struct {
+		name    string
+		fn      string
+		args    []float64
+		want    float64
+		wantErr error
+	}{
+		{
+			name: "square root",
+			fn:   "sqrt",
+			args: []float64{9},
+			want: 3,
+		},
+		{
+			name:    "logarithm of negative number",
+			fn:      "ln",
+			args:    []float64{-9},
+			wantErr: fn.ErrOutOfDomain,
+		},
+		{
+			name: "absolute value",
+			fn:   "abs",
+			args: []float64{-2.5},
+			want: 2.5,
+		},
+		{
+			name: "floor",
+			fn:   "floor",
+			args: []float64{-2.5},
+			want: -3,
+		},
+		{
+			name: "ceil",
+			fn:   "ceil",
+			args: []float64{2.1},
+			want: 3,
+		},
+		{
+			name: "round half away from zero",
+			fn:   "round",
+			args: []float64{-2.5},
+			want: -3,
+		},
+		{
+			name: "sine",
+			fn:   "sin",
+			args: []float64{math.Pi / 2},
+			want: 1,
+		},
+		{
+			name: "cosine",
+			fn:   "cos",
+			args: []float64{0},
+			want: 1,
+		},
+		{
+			name: "tangent",
+			fn:   "tan",
+			args: []float64{math.Pi / 4},
+			want: 1,
+		},
+		{
+			name: "arc sine",
+			fn:   "asin",
+			args: []float64{1},
+			want: math.Pi / 2,
+		},
+		{
+			name:    "arc cosine out of domain",
+			fn:      "acos",
+			args:    []float64{1.5},
+			wantErr: fn.ErrOutOfDomain,
+		},
+		{
+			name: "arc tangent",
+			fn:   "atan",
+			args: []float64{1},
+			want: math.Pi / 4,
+		},
+		{
+			name: "two-argument arc tangent",
+			fn:   "atan2",
+			args: []float64{1, -1},
+			want: 3 * math.Pi / 4,
+		},
+		{
+			name: "exponential",
+			fn:   "exp",
+			args: []float64{1},
+			want: math.E,
+		},
+		{
+			name: "natural logarithm",
+			fn:   "ln",
+			args: []float64{math.E},
+			want: 1,
+		},
+		{
+			name:    "natural logarithm of zero",
+			fn:      "ln",
+			args:    []float64{0},
+			wantErr: fn.ErrOutOfDomain,
+		},
+		{
+			name: "logarithm without base",
+			fn:   "log",
+			args: []float64{math.E * math.E},
+			want: 2,
+		},
+		{
+			name: "logarithm with base",
+			fn:   "log",
+			args: []float64{81, 3},
+			want: 4,
+		},
+		{
+			name:    "logarithm with base one",
+			fn:      "log",
+			args:    []float64{81, 1},
+			wantErr: fn.ErrOutOfDomain,
+		},
+		{
+			name: "binary logarithm",
+			fn:   "log2",
+			args: []float64{1024},
+			want: 10,
+		},
+		{
+			name: "common logarithm",
+			fn:   "log10",
+			args: []float64{0.001},
+			want: -3,
+		},
+		{
+			name: "minimum of multiple arguments",
+			fn:   "min",
+			args: []float64{3, -1, 2},
+			want: -1,
+		},
+		{
+			name: "maximum of single argument",
+			fn:   "max",
+			args: []float64{3},
+			want: 3,
+		},
+		{
+			name:    "maximum without arguments",
+			fn:      "max",
+			args:    []float64{},
+			wantErr: fn.ErrInvalidArgumentCount,
+		},
+		{
+			name: "hypotenuse",
+			fn:   "hypot",
+			args: []float64{3, 4},
+			want: 5,
+		},
+		{
+			name:    "too many arguments",
+			fn:      "hypot",
+			args:    []float64{3, 4, 5},
+			wantErr: fn.ErrInvalidArgumentCount,
+		},
+		{
+			name:    "too few arguments",
+			fn:      "sqrt",
+			args:    []float64{},
+			wantErr: fn.ErrInvalidArgumentCount,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := fn.GetFunction(tt.fn)
+			if err != nil {
+				t.Fatalf("GetFunction(%q) error = %v", tt.fn, err)
+			}
+
+			args := make([]value.Value, 0, len(tt.args))
+			for _, arg := range tt.args {
+				args = append(args, value.Float(arg))
+			}
+
+			result, err := f.Evaluate(args)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Evaluate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			got, err := value.ToFloat(result)
+			if err != nil {
+				t.Fatalf("ToFloat() error = %v", err)
+			}
+			if math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFunction_EvaluateComplex(t *testing.T) {
+	tests := []struct {
+		name    string
+		fn      string
+		args    []value.Value
+		want    complex128
+		wantErr error
+	}{
+		{
+			name: "square root of negative number",
+			fn:   "sqrt",
+			args: []value.Value{value.Float(-9)},
+			want: 3i,
+		},
+		{
+			name: "square root of complex number",
+			fn:   "sqrt",
+			args: []value.Value{value.Complex(-3 + 4i)},
+			want: 1 + 2i,
+		},
+		{
+			name: "magnitude",
+			fn:   "abs",
+			args: []value.Value{value.Complex(3 + 4i)},
+			want: 5,
+		},
+		{
+			name: "euler's formula",
+			fn:   "exp",
+			args: []value.Value{value.Complex(complex(0, math.Pi))},
+			want: -1,
+		},
+		{
+			name: "imaginary part",
+			fn:   "im",
+			args: []value.Value{value.Complex(3 - 4i)},
+			want: -4,
+		},
+		{
+			name: "conjugate",
+			fn:   "conj",
+			args: []value.Value{value.Complex(3 - 4i)},
+			want: 3 + 4i,
+		},
+		{
+			name: "phase of negative number",
+			fn:   "arg",
+			args: []value.Value{value.Float(-1)},
+			want: math.Pi,
+		},
+		{
+			name:    "complex argument is not supported",
+			fn:      "floor",
+			args:    []value.Value{value.Complex(1 + 1i)},
+			wantErr: value.ErrInvalidType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := fn.GetFunction(tt.fn)
+			if err != nil {
+				t.Fatalf("GetFunction(%q) error = %v", tt.fn, err)
+			}
+
+			result, err := f.Evaluate(tt.args)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Evaluate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			got, err := value.ToComplex(result)
+			if err != nil {
+				t.Fatalf("ToComplex() error = %v", err)
+			}
+			if cmplx.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetFunction_NotFound(t *testing.T) {
+	_, err := fn.GetFunction("foo")
+	if !errors.Is(err, fn.ErrFunctionNotFound) {
+		t.Errorf("GetFunction() error = %v, want %v", err, fn.ErrFunctionNotFound)
+	}
+}
@@ -0,0 +1,150 @@
+package function
+
+import (
+	"fmt"
+	"math/big"
+
+	"simplecalc/pkg/parser/operator"
+	"simplecalc/pkg/parser/value"
+)
+
+func init() {
+	registerFunction(&Function{
+		// sum(v) adds the elements of the list, sum(a, b, ...) adds the arguments
+		name:    "sum",
+		minArgs: 1,
+		maxArgs: Variadic,
+		values: func(args []value.Value) (value.Value, error) {
+			return sum("sum", flatten(args))
+		},
+	})
+	registerFunction(&Function{
+		name:    "product",
+		minArgs: 1,
+		maxArgs: Variadic,
+		values: func(args []value.Value) (value.Value, error) {
+			return product("product", flatten(args))
+		},
+	})
+	registerFunction(&Function{
+		name:    "mean",
+		minArgs: 1,
+		maxArgs: Variadic,
+		values: func(args []value.Value) (value.Value, error) {
+			elems := flatten(args)
+			if len(elems) == 0 {
+				return nil, fmt.Errorf("%w: 'mean' of empty list", ErrOutOfDomain)
+			}
+
+			total, err := sum("mean", elems)
+			if err != nil {
+				return nil, err
+			}
+			// Keep the mean of the exact numbers exact
+			var count value.Value = value.Float(len(elems))
+			if digits := value.Digits(total); digits > 0 {
+				count = value.NewRational(big.NewRat(int64(len(elems)), 1), digits)
+			}
+			return operator.GetOperator("/").Evaluate([]value.Value{total, count})
+		},
+	})
+	registerFunction(&Function{
+		name:    "len",
+		minArgs: 1,
+		maxArgs: 1,
+		values: func(args []value.Value) (value.Value, error) {
+			list, err := toList("len", args[0])
+			if err != nil {
+				return nil, err
+			}
+			return value.Float(len(list)), nil
+		},
+	})
+	registerFunction(&Function{
+		name:    "dot",
+		minArgs: 2,
+		maxArgs: 2,
+		values: func(args []value.Value) (value.Value, error) {
+			x, err := toList("dot", args[0])
+			if err != nil {
+				return nil, err
+			}
+			y, err := toList("dot", args[1])
+			if err != nil {
+				return nil, err
+			}
+			if len(x) != len(y) {
+				return nil, fmt.Errorf("%w: 'dot' of lists with %d and %d elements", value.ErrLengthMismatch, len(x), len(y))
+			}
+
+			products, err := operator.GetOperator("*").Evaluate([]value.Value{x, y})
+			if err != nil {
+				return nil, err
+			}
+			return sum("dot", products.(value.List))
+		},
+	})
+}
+
+// flatten returns the elements of the list arguments and the other arguments
+func flatten(args []value.Value) []value.Value {
+	elems := make([]value.Value, 0, len(args))
+	for _, arg := range args {
+		if list, ok := arg.(value.List); ok {
+			elems = append(elems, list...)
+		} else {
+			elems = append(elems, arg)
+		}
+	}
+
+	return elems
+}
+
+// sum adds the values with the "+" operator, so the exact numbers,
+// the complex numbers and the units are kept, the sum of nothing is 0.
+func sum(name string, elems []value.Value) (value.Value, error) {
+	if len(elems) == 0 {
+		return value.Float(0), nil
+	}
+
+	add := operator.GetOperator("+")
+	total := elems[0]
+	for _, elem := range elems[1:] {
+		var err error
+		total, err = add.Evaluate([]value.Value{total, elem})
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate '%s': %w", name, err)
+		}
+	}
+
+	return total, nil
+}
+
+// product multiplies the values with the "*" operator like sum,
+// the product of nothing is 1.
+func product(name string, elems []value.Value) (value.Value, error) {
+	if len(elems) == 0 {
+		return value.Float(1), nil
+	}
+
+	multiply := operator.GetOperator("*")
+	total := elems[0]
+	for _, elem := range elems[1:] {
+		var err error
+		total, err = multiply.Evaluate([]value.Value{total, elem})
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate '%s': %w", name, err)
+		}
+	}
+
+	return total, nil
+}
+
+func toList(name string, v value.Value) (value.List, error) {
+	list, ok := v.(value.List)
+	if !ok {
+		return nil, fmt.Errorf("%w: argument of '%s' must be a list, got %s", value.ErrInvalidType, name, v.Kind())
+	}
+
+	return list, nil
+}
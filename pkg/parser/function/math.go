@@ -0,0 +1,124 @@
+package function
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+
+	"simplecalc/pkg/parser/value"
+)
+
+func init() {
+	registerFunction(withComplex(withExact(unary("sqrt", func(x float64) (float64, error) {
+		if x < 0 {
+			// The square root of a negative number is imaginary
+			return 0, errComplex
+		}
+		return math.Sqrt(x), nil
+	}), exactUnary(exactSqrt)), complexUnary(cmplx.Sqrt)))
+	registerFunction(withComplex(withExact(unary("abs", wrap(math.Abs)), exactUnary(exactAbs)), complexUnary(func(x complex128) complex128 {
+		return complex(cmplx.Abs(x), 0)
+	})))
+	registerFunction(withExact(unary("floor", wrap(math.Floor)), exactUnary(value.Floor)))
+	registerFunction(withExact(unary("ceil", wrap(math.Ceil)), exactUnary(value.Ceil)))
+	registerFunction(withExact(unary("round", wrap(math.Round)), exactUnary(value.Round)))
+	registerFunction(withComplex(unary("exp", wrap(math.Exp)), complexUnary(cmplx.Exp)))
+	registerFunction(withComplex(unary("ln", logarithm("ln", math.Log)), complexUnary(cmplx.Log)))
+	registerFunction(unary("log2", logarithm("log2", math.Log2)))
+	registerFunction(unary("log10", logarithm("log10", math.Log10)))
+	registerFunction(&Function{
+		// log(x) is the natural logarithm, log(x, b) is the logarithm of x to base b
+		name:    "log",
+		minArgs: 1,
+		maxArgs: 2,
+		eval: func(args []float64) (float64, error) {
+			x, err := logarithm("log", math.Log)(args[0])
+			if err != nil || len(args) == 1 {
+				return x, err
+			}
+
+			b := args[1]
+			if b <= 0 || b == 1 {
+				return 0, fmt.Errorf("%w: 'log' with base %g", ErrOutOfDomain, b)
+			}
+			return x / math.Log(b), nil
+		},
+	})
+	registerFunction(&Function{
+		name:    "min",
+		minArgs: 1,
+		maxArgs: Variadic,
+		series:  true,
+		eval: func(args []float64) (float64, error) {
+			result := args[0]
+			for _, arg := range args[1:] {
+				result = math.Min(result, arg)
+			}
+			return result, nil
+		},
+		exact: exactExtremum(-1),
+	})
+	registerFunction(&Function{
+		name:    "max",
+		minArgs: 1,
+		maxArgs: Variadic,
+		series:  true,
+		eval: func(args []float64) (float64, error) {
+			result := args[0]
+			for _, arg := range args[1:] {
+				result = math.Max(result, arg)
+			}
+			return result, nil
+		},
+		exact: exactExtremum(1),
+	})
+	registerFunction(binary("hypot", wrap2(math.Hypot)))
+}
+
+// unary creates a function that accepts exactly one argument
+func unary(name string, fn func(float64) (float64, error)) *Function {
+	return &Function{
+		name:    name,
+		minArgs: 1,
+		maxArgs: 1,
+		eval: func(args []float64) (float64, error) {
+			return fn(args[0])
+		},
+	}
+}
+
+// binary creates a function that accepts exactly two arguments
+func binary(name string, fn func(float64, float64) (float64, error)) *Function {
+	return &Function{
+		name:    name,
+		minArgs: 2,
+		maxArgs: 2,
+		eval: func(args []float64) (float64, error) {
+			return fn(args[0], args[1])
+		},
+	}
+}
+
+// wrap adapts a function from the math package which never fails
+func wrap(fn func(float64) float64) func(float64) (float64, error) {
+	return func(x float64) (float64, error) {
+		return fn(x), nil
+	}
+}
+
+// wrap2 adapts a two-argument function from the math package which never fails
+func wrap2(fn func(float64, float64) float64) func(float64, float64) (float64, error) {
+	return func(x, y float64) (float64, error) {
+		return fn(x, y), nil
+	}
+}
+
+// logarithm rejects non-positive numbers before calling the logarithm function
+func logarithm(name string, fn func(float64) float64) func(float64) (float64, error) {
+	return func(x float64) (float64, error) {
+		if x <= 0 {
+			return 0, fmt.Errorf("%w: '%s' of non-positive number %g", ErrOutOfDomain, name, x)
+		}
+		return fn(x), nil
+	}
+}
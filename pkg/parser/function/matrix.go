@@ -0,0 +1,321 @@
+package function
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/cmplx"
+
+	"simplecalc/pkg/parser/operator"
+	"simplecalc/pkg/parser/value"
+)
+
+var ErrSingularMatrix = fmt.Errorf("singular matrix")
+
+// singularTolerance is the relative size of a pivot of float64 numbers
+// which is treated as zero, the rounding errors of the elimination
+// would make a singular matrix like [[0.1, 0.2], [0.3, 0.6]] invertible.
+const singularTolerance = 1e-12
+
+func init() {
+	registerFunction(&Function{
+		// transpose of a vector like [1, 2] is a column like [[1], [2]]
+		name:    "transpose",
+		minArgs: 1,
+		maxArgs: 1,
+		values: func(args []value.Value) (value.Value, error) {
+			m, err := toMatrix("transpose", args[0])
+			if err != nil {
+				return nil, err
+			}
+			return m.Transpose().List(), nil
+		},
+	})
+	registerFunction(&Function{
+		name:    "det",
+		minArgs: 1,
+		maxArgs: 1,
+		values: func(args []value.Value) (value.Value, error) {
+			m, err := toSquareMatrix("det", args[0])
+			if err != nil {
+				return nil, err
+			}
+			return determinant(m)
+		},
+	})
+	registerFunction(&Function{
+		name:    "inverse",
+		minArgs: 1,
+		maxArgs: 1,
+		values: func(args []value.Value) (value.Value, error) {
+			m, err := toSquareMatrix("inverse", args[0])
+			if err != nil {
+				return nil, err
+			}
+			x, err := solve("inverse", m, identity(len(m), m))
+			if err != nil {
+				return nil, err
+			}
+			return x.List(), nil
+		},
+	})
+	registerFunction(&Function{
+		// solve(A, b) returns x of A @ x == b, b is a vector or a matrix
+		name:    "solve",
+		minArgs: 2,
+		maxArgs: 2,
+		values: func(args []value.Value) (value.Value, error) {
+			a, err := toSquareMatrix("solve", args[0])
+			if err != nil {
+				return nil, err
+			}
+			b, err := toMatrix("solve", args[1])
+			if err != nil {
+				return nil, err
+			}
+
+			// A vector is a column on the right side
+			list := args[1].(value.List)
+			isVector := list[0].Kind() != value.KindList
+			if isVector {
+				b = b.Transpose()
+			}
+			if len(b) != len(a) {
+				return nil, &value.ShapeError{Op: "solve", Shapes: []value.Shape{a.Shape(), b.Shape()}}
+			}
+
+			x, err := solve("solve", a, b)
+			if err != nil {
+				return nil, err
+			}
+			if isVector {
+				return value.List(x.Transpose()[0]), nil
+			}
+			return x.List(), nil
+		},
+	})
+}
+
+func toMatrix(name string, v value.Value) (value.Matrix, error) {
+	m, err := value.ToMatrix(v)
+	if err != nil {
+		return nil, fmt.Errorf("argument of '%s' must be a matrix: %w", name, err)
+	}
+
+	return m, nil
+}
+
+func toSquareMatrix(name string, v value.Value) (value.Matrix, error) {
+	m, err := toMatrix(name, v)
+	if err != nil {
+		return nil, err
+	}
+	if !m.IsSquare() {
+		return nil, &value.ShapeError{Op: name, Shapes: []value.Shape{m.Shape()}}
+	}
+
+	return m, nil
+}
+
+// identity returns the identity matrix of size n,
+// its numbers are exact if the elements of m are exact.
+func identity(n int, m value.Matrix) value.Matrix {
+	var digits uint
+	for _, row := range m {
+		for _, elem := range row {
+			digits = max(digits, value.Digits(elem))
+		}
+	}
+
+	zero, one := value.Value(value.Float(0)), value.Value(value.Float(1))
+	if digits > 0 {
+		zero = value.NewRational(new(big.Rat), digits)
+		one = value.NewRational(big.NewRat(1, 1), digits)
+	}
+
+	id := make(value.Matrix, 0, n)
+	for i := range n {
+		row := make([]value.Value, 0, n)
+		for j := range n {
+			if i == j {
+				row = append(row, one)
+			} else {
+				row = append(row, zero)
+			}
+		}
+		id = append(id, row)
+	}
+
+	return id
+}
+
+// determinant is the product of the pivots of the elimination,
+// the sign is changed for each swap of rows.
+func determinant(m value.Matrix) (value.Value, error) {
+	a, _, swaps, err := eliminate(m, nil)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		return value.Float(0), nil
+	}
+
+	det := a[0][0]
+	for i := 1; i < len(a); i++ {
+		if det, err = evaluate("*", det, a[i][i]); err != nil {
+			return nil, err
+		}
+	}
+	if swaps%2 == 1 {
+		return value.Negate(det)
+	}
+
+	return det, nil
+}
+
+// solve returns x of a @ x == b with the Gaussian elimination
+// and the back substitution.
+func solve(name string, a, b value.Matrix) (value.Matrix, error) {
+	a, b, _, err := eliminate(a, b)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		return nil, fmt.Errorf("%w: '%s' of matrix without inverse", ErrSingularMatrix, name)
+	}
+
+	n := len(a)
+	x := make(value.Matrix, n)
+	for i := n - 1; i >= 0; i-- {
+		x[i] = make([]value.Value, len(b[i]))
+		for c := range b[i] {
+			sum := b[i][c]
+			for j := i + 1; j < n; j++ {
+				product, err := evaluate("*", a[i][j], x[j][c])
+				if err != nil {
+					return nil, err
+				}
+				if sum, err = evaluate("-", sum, product); err != nil {
+					return nil, err
+				}
+			}
+			if x[i][c], err = evaluate("/", sum, a[i][i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return x, nil
+}
+
+// eliminate transforms the copies of the square matrix a and the matrix b
+// with the same row operations until a is upper triangular,
+// the largest element of each column is used as the pivot.
+// It returns nil matrices if a is singular.
+func eliminate(a, b value.Matrix) (value.Matrix, value.Matrix, int, error) {
+	a, b = clone(a), clone(b)
+
+	// The pivots of the exact numbers are only zero when they are 0
+	var tolerance float64
+	for _, row := range a {
+		for _, elem := range row {
+			if value.Digits(elem) > 0 {
+				continue
+			}
+			mag, err := magnitude(elem)
+			if err != nil {
+				return nil, nil, 0, err
+			}
+			tolerance = max(tolerance, mag*singularTolerance)
+		}
+	}
+
+	swaps := 0
+	for k := range a {
+		pivot, pivotMag := k, -1.0
+		for i := k; i < len(a); i++ {
+			mag, err := magnitude(a[i][k])
+			if err != nil {
+				return nil, nil, 0, err
+			}
+			if mag > pivotMag {
+				pivot, pivotMag = i, mag
+			}
+		}
+		if pivotMag == 0 || pivotMag <= tolerance && value.Digits(a[pivot][k]) == 0 {
+			return nil, nil, 0, nil
+		}
+		if pivot != k {
+			a[k], a[pivot] = a[pivot], a[k]
+			if b != nil {
+				b[k], b[pivot] = b[pivot], b[k]
+			}
+			swaps++
+		}
+
+		for i := k + 1; i < len(a); i++ {
+			factor, err := evaluate("/", a[i][k], a[k][k])
+			if err != nil {
+				return nil, nil, 0, err
+			}
+			if err := subtractRow(a[i], a[k], factor); err != nil {
+				return nil, nil, 0, err
+			}
+			if b != nil {
+				if err := subtractRow(b[i], b[k], factor); err != nil {
+					return nil, nil, 0, err
+				}
+			}
+		}
+	}
+
+	return a, b, swaps, nil
+}
+
+// subtractRow subtracts the row multiplied by the factor from the target
+func subtractRow(target, row []value.Value, factor value.Value) error {
+	for j := range target {
+		product, err := evaluate("*", factor, row[j])
+		if err != nil {
+			return err
+		}
+		if target[j], err = evaluate("-", target[j], product); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func clone(m value.Matrix) value.Matrix {
+	if m == nil {
+		return nil
+	}
+
+	c := make(value.Matrix, 0, len(m))
+	for _, row := range m {
+		c = append(c, append([]value.Value(nil), row...))
+	}
+
+	return c
+}
+
+// magnitude returns the absolute value of a number to choose the pivot
+func magnitude(v value.Value) (float64, error) {
+	if c, ok := v.(value.Complex); ok {
+		return cmplx.Abs(complex128(c)), nil
+	}
+
+	x, err := value.ToFloat(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid element of matrix: %w", err)
+	}
+
+	return math.Abs(x), nil
+}
+
+// evaluate applies the operator to the elements of matrices,
+// so the exact numbers and the complex numbers are kept.
+func evaluate(literal string, x, y value.Value) (value.Value, error) {
+	return operator.GetOperator(literal).Evaluate([]value.Value{x, y})
+}
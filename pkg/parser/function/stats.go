@@ -0,0 +1,222 @@
+package function
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+
+	"simplecalc/pkg/parser/value"
+)
+
+func init() {
+	registerFunction(&Function{
+		name:    "median",
+		minArgs: 1,
+		maxArgs: Variadic,
+		series:  true,
+		eval: func(args []float64) (float64, error) {
+			sorted := slices.Sorted(slices.Values(args))
+			mid := len(sorted) / 2
+			if len(sorted)%2 == 1 {
+				return sorted[mid], nil
+			}
+			return (sorted[mid-1] + sorted[mid]) / 2, nil
+		},
+		exact: func(args []value.Value) (value.Value, error) {
+			sorted, err := sortValues(args)
+			if err != nil {
+				return nil, err
+			}
+			mid := len(sorted) / 2
+			if len(sorted)%2 == 1 {
+				return sorted[mid], nil
+			}
+			total, err := evaluate("+", sorted[mid-1], sorted[mid])
+			if err != nil {
+				return nil, err
+			}
+			return evaluate("/", total, exactInt(2, args))
+		},
+	})
+	registerFunction(&Function{
+		// variance is the sample variance with n - 1 degrees of freedom
+		name:    "variance",
+		minArgs: 1,
+		maxArgs: Variadic,
+		series:  true,
+		eval: func(args []float64) (float64, error) {
+			return variance("variance", args)
+		},
+		exact: func(args []value.Value) (value.Value, error) {
+			return exactVariance("variance", args)
+		},
+	})
+	registerFunction(&Function{
+		// stdev is the sample standard deviation
+		name:    "stdev",
+		minArgs: 1,
+		maxArgs: Variadic,
+		series:  true,
+		eval: func(args []float64) (float64, error) {
+			v, err := variance("stdev", args)
+			return math.Sqrt(v), err
+		},
+		exact: func(args []value.Value) (value.Value, error) {
+			v, err := exactVariance("stdev", args)
+			if err != nil {
+				return nil, err
+			}
+			return exactSqrt(v)
+		},
+	})
+	registerFunction(&Function{
+		// percentile(p, ...) interpolates between the closest ranks linearly,
+		// so percentile(50, ...) is the median.
+		name:    "percentile",
+		minArgs: 2,
+		maxArgs: Variadic,
+		series:  true,
+		eval: func(args []float64) (float64, error) {
+			p := args[0]
+			if p < 0 || p > 100 || math.IsNaN(p) {
+				return 0, fmt.Errorf("%w: 'percentile' of %g, must be between 0 and 100", ErrOutOfDomain, p)
+			}
+
+			sorted := slices.Sorted(slices.Values(args[1:]))
+			rank := p / 100 * float64(len(sorted)-1)
+			lower := math.Floor(rank)
+			upper := math.Ceil(rank)
+			return sorted[int(lower)] + (rank-lower)*(sorted[int(upper)]-sorted[int(lower)]), nil
+		},
+	})
+	registerFunction(&Function{
+		// mode is the most common number, the smallest one of the ties
+		name:    "mode",
+		minArgs: 1,
+		maxArgs: Variadic,
+		series:  true,
+		eval: func(args []float64) (float64, error) {
+			counts := map[float64]int{}
+			result := math.NaN()
+			for _, x := range args {
+				counts[x]++
+				if math.IsNaN(result) || counts[x] > counts[result] ||
+					(counts[x] == counts[result] && x < result) {
+					result = x
+				}
+			}
+			return result, nil
+		},
+		exact: func(args []value.Value) (value.Value, error) {
+			sorted, err := sortValues(args)
+			if err != nil {
+				return nil, err
+			}
+
+			// The equal numbers are next to each other after sorting
+			result, best := sorted[0], 0
+			for start := 0; start < len(sorted); {
+				end := start + 1
+				for end < len(sorted) {
+					c, err := value.Compare(sorted[start], sorted[end])
+					if err != nil {
+						return nil, err
+					}
+					if c != 0 {
+						break
+					}
+					end++
+				}
+				if end-start > best {
+					result, best = sorted[start], end-start
+				}
+				start = end
+			}
+			return result, nil
+		},
+	})
+}
+
+// variance calculates the sample variance with the Welford's algorithm,
+// which doesn't lose the precision like the sum of squares
+// when the numbers are large and close to each other.
+func variance(name string, args []float64) (float64, error) {
+	if len(args) < 2 {
+		return 0, fmt.Errorf("%w: '%s' of less than 2 numbers", ErrOutOfDomain, name)
+	}
+
+	var mean, m2 float64
+	for i, x := range args {
+		delta := x - mean
+		mean += delta / float64(i+1)
+		m2 += delta * (x - mean)
+	}
+
+	return m2 / float64(len(args)-1), nil
+}
+
+// exactVariance calculates the sample variance of the exact numbers
+// from the sum of the squared deviations, which has no rounding errors.
+func exactVariance(name string, args []value.Value) (value.Value, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("%w: '%s' of less than 2 numbers", ErrOutOfDomain, name)
+	}
+
+	total, err := sum(name, args)
+	if err != nil {
+		return nil, err
+	}
+	mean, err := evaluate("/", total, exactInt(len(args), args))
+	if err != nil {
+		return nil, err
+	}
+
+	squares := make([]value.Value, 0, len(args))
+	for _, x := range args {
+		deviation, err := evaluate("-", x, mean)
+		if err != nil {
+			return nil, err
+		}
+		square, err := evaluate("*", deviation, deviation)
+		if err != nil {
+			return nil, err
+		}
+		squares = append(squares, square)
+	}
+
+	total, err = sum(name, squares)
+	if err != nil {
+		return nil, err
+	}
+
+	return evaluate("/", total, exactInt(len(args)-1, args))
+}
+
+// sortValues returns the sorted copy of the numbers
+func sortValues(args []value.Value) ([]value.Value, error) {
+	var err error
+	sorted := slices.Clone(args)
+	slices.SortStableFunc(sorted, func(x, y value.Value) int {
+		c, cmpErr := value.Compare(x, y)
+		if cmpErr != nil {
+			err = cmpErr
+		}
+		return c
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sorted, nil
+}
+
+// exactInt returns the integer as an exact number with the digits of the arguments
+func exactInt(n int, args []value.Value) value.Value {
+	var digits uint
+	for _, arg := range args {
+		digits = max(digits, value.Digits(arg))
+	}
+
+	return value.NewRational(big.NewRat(int64(n), 1), digits)
+}
@@ -0,0 +1,27 @@
+package function
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+)
+
+func init() {
+	registerFunction(withComplex(unary("sin", wrap(math.Sin)), complexUnary(cmplx.Sin)))
+	registerFunction(withComplex(unary("cos", wrap(math.Cos)), complexUnary(cmplx.Cos)))
+	registerFunction(withComplex(unary("tan", wrap(math.Tan)), complexUnary(cmplx.Tan)))
+	registerFunction(unary("asin", inverseTrig("asin", math.Asin)))
+	registerFunction(unary("acos", inverseTrig("acos", math.Acos)))
+	registerFunction(unary("atan", wrap(math.Atan)))
+	registerFunction(binary("atan2", wrap2(math.Atan2)))
+}
+
+// inverseTrig rejects arguments outside of [-1, 1] before calling the function
+func inverseTrig(name string, fn func(float64) float64) func(float64) (float64, error) {
+	return func(x float64) (float64, error) {
+		if x < -1 || x > 1 {
+			return 0, fmt.Errorf("%w: '%s' of %g is not in [-1, 1]", ErrOutOfDomain, name, x)
+		}
+		return fn(x), nil
+	}
+}
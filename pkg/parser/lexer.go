@@ -1,7 +1,10 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
+	"math/big"
+	"strconv"
 	"strings"
 	"unicode"
 
@@ -31,14 +34,6 @@ func NewLexer(input string) (*Lexer, error) {
 }
 
 func (l *Lexer) parseTokens(input string) error {
-	// Remove all whitespace characters (spaces, tabs, newlines, etc.)
-	input = strings.Map(func(r rune) rune {
-		if unicode.IsSpace(r) {
-			return -1
-		}
-		return r
-	}, input)
-
 	// lexer.cursor has two purposes:
 	// 1. It is used to track the current position in the input string
 	// 2. It is used to track the current position in the tokens slice
@@ -49,6 +44,14 @@ func (l *Lexer) parseTokens(input string) error {
 	for l.cursor < len(input) {
 		char := input[l.cursor]
 
+		// Skip whitespace characters (spaces, tabs, newlines, etc.) between tokens,
+		// they are kept in the input to separate word operators like "rem"
+		// from variable names around them.
+		if unicode.IsSpace(rune(char)) {
+			l.cursor++
+			continue
+		}
+
 		// Return operator and new cursor if char is an operator
 		op, newCursor := operator.LexWithOperator(&input, l.cursor)
 		if op != nil {
@@ -79,6 +82,11 @@ func (l *Lexer) parseTokens(input string) error {
 				if err != nil {
 					return fmt.Errorf("failed to read variable name: %w", err)
 				}
+			} else if char == '$' {
+				err := l.readResultReference(input)
+				if err != nil {
+					return fmt.Errorf("failed to read result reference: %w", err)
+				}
 			} else {
 				return fmt.Errorf("illegal character %s", string(char))
 			}
@@ -113,6 +121,16 @@ func (l *Lexer) Peek() Token {
 	return l.tokens[l.cursor]
 }
 
+// PeekNth returns the nth token after the next one without consuming any,
+// PeekNth(0) is the same as Peek.
+func (l *Lexer) PeekNth(n int) Token {
+	if l.cursor+n >= len(l.tokens) {
+		return NewEOFToken()
+	}
+
+	return l.tokens[l.cursor+n]
+}
+
 func (l *Lexer) HasNext() bool {
 	return l.cursor < len(l.tokens)
 }
@@ -137,7 +155,24 @@ func (l *Lexer) String() string {
 	return sb.String()
 }
 
+// numberBases maps the second character of a base prefix to its base
+var numberBases = map[byte]struct {
+	name string
+	base int
+}{
+	'x': {"hexadecimal", 16},
+	'b': {"binary", 2},
+	'o': {"octal", 8},
+}
+
 func (l *Lexer) readNumber(input string) error {
+	// Check for integer literals with a base prefix like 0xFF, 0b1010 and 0o755
+	if l.cursor < len(input)-1 && input[l.cursor] == '0' {
+		if _, ok := numberBases[byte(unicode.ToLower(rune(input[l.cursor+1])))]; ok {
+			return l.readPrefixedInteger(input)
+		}
+	}
+
 	var sb strings.Builder
 
 	// Check for negative number
@@ -147,7 +182,8 @@ func (l *Lexer) readNumber(input string) error {
 	}
 
 	hasDecimal := false
-	for l.cursor < len(input) && (unicode.IsDigit(rune(input[l.cursor])) || input[l.cursor] == '.') {
+	for l.cursor < len(input) &&
+		(unicode.IsDigit(rune(input[l.cursor])) || input[l.cursor] == '.' || input[l.cursor] == '_') {
 		if input[l.cursor] == '.' {
 			if hasDecimal {
 				// If we already have more than one decimal point, it's illegal
@@ -157,6 +193,15 @@ func (l *Lexer) readNumber(input string) error {
 			}
 			hasDecimal = true
 		}
+		if input[l.cursor] == '_' {
+			// Digit separators like 1_000_000 must be between two digits
+			prevIsDigit := sb.Len() > 0 && unicode.IsDigit(rune(sb.String()[sb.Len()-1]))
+			nextIsDigit := l.cursor < len(input)-1 && unicode.IsDigit(rune(input[l.cursor+1]))
+			if !prevIsDigit || !nextIsDigit {
+				sb.WriteByte(input[l.cursor])
+				return fmt.Errorf("invalid digit separator '_' in number %s", sb.String())
+			}
+		}
 		sb.WriteByte(input[l.cursor])
 		l.cursor++
 	}
@@ -165,8 +210,106 @@ func (l *Lexer) readNumber(input string) error {
 		return fmt.Errorf("invalid number: %c", input[l.cursor])
 	}
 
-	l.tokens = append(l.tokens, NewAtomNumToken(sb.String()))
+	// Check for scientific notation like 6.02e23 and 1.5E-9, the exponent is
+	// only read if it has digits, so "2e" is still the number 2 and variable e
+	if l.cursor < len(input) && (input[l.cursor] == 'e' || input[l.cursor] == 'E') {
+		end := l.cursor + 1
+		if end < len(input) && (input[end] == '+' || input[end] == '-') {
+			end++
+		}
+		if end < len(input) && unicode.IsDigit(rune(input[end])) {
+			for end < len(input) && unicode.IsDigit(rune(input[end])) {
+				end++
+			}
+			sb.WriteString(input[l.cursor:end])
+			l.cursor = end
+		}
+	}
+
+	// Check for imaginary numbers like 4i and 2.5j, the suffix is
+	// only read at the end of a word, so "2if" is not imaginary
+	if l.cursor < len(input) && (input[l.cursor] == 'i' || input[l.cursor] == 'j') {
+		end := l.cursor + 1
+		if end == len(input) ||
+			(input[end] != '_' && !unicode.IsLetter(rune(input[end])) && !unicode.IsDigit(rune(input[end]))) {
+			sb.WriteByte(input[l.cursor])
+			l.cursor = end
+		}
+	}
+
+	// The numbers like 1e400 can't be float64, even in the exact modes
+	// the float64 value is kept for the functions without exact versions
+	literal := sb.String()
+	if _, err := strconv.ParseFloat(strings.TrimRight(strings.ReplaceAll(literal, "_", ""), "ij"), 64); err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			return fmt.Errorf("number '%s' is out of range", literal)
+		}
+		return fmt.Errorf("invalid number %s", literal)
+	}
+
+	l.tokens = append(l.tokens, NewAtomNumToken(literal))
+
+	return nil
+}
+
+// readPrefixedInteger reads an integer literal with a base prefix
+// like 0xFF, 0b1010 or 0o755, the digits may be separated by underscores.
+func (l *Lexer) readPrefixedInteger(input string) error {
+	start := l.cursor
+	prefix := numberBases[byte(unicode.ToLower(rune(input[l.cursor+1])))]
+	l.cursor += 2
+
+	// Read all the following letters and digits to report
+	// the whole literal on error like "0b102" or "0xFG"
+	for l.cursor < len(input) &&
+		(unicode.IsLetter(rune(input[l.cursor])) ||
+			unicode.IsDigit(rune(input[l.cursor])) ||
+			input[l.cursor] == '_') {
+		l.cursor++
+	}
+	literal := input[start:l.cursor]
+	if l.cursor < len(input) && input[l.cursor] == '.' {
+		return fmt.Errorf("invalid decimal point in %s literal '%s.'", prefix.name, literal)
+	}
+
+	digits := literal[2:]
+	if strings.Trim(digits, "_") == "" {
+		return fmt.Errorf("missing digits in %s literal '%s'", prefix.name, literal)
+	}
+	for _, digit := range digits {
+		if digit == '_' {
+			continue
+		}
+		if _, err := strconv.ParseUint(string(digit), prefix.base, 8); err != nil {
+			return fmt.Errorf("invalid digit '%c' in %s literal '%s'", digit, prefix.name, literal)
+		}
+	}
+
+	// The literals beyond int64 are exact in the exact modes,
+	// the float mode checks the range when evaluating them
+	if _, ok := new(big.Int).SetString(literal, 0); !ok {
+		return fmt.Errorf("invalid digit separator '_' in %s literal '%s'", prefix.name, literal)
+	}
+
+	l.tokens = append(l.tokens, NewAtomNumToken(literal))
+
+	return nil
+}
+
+// readResultReference reads the reference to the previous result like "$3"
+// as a variable name, which can't be assigned by the input.
+func (l *Lexer) readResultReference(input string) error {
+	start := l.cursor
+	l.cursor++ // Skip the '$' character
+	for l.cursor < len(input) && unicode.IsDigit(rune(input[l.cursor])) {
+		l.cursor++
+	}
+
+	if l.cursor == start+1 {
+		return fmt.Errorf("missing number after '$'")
+	}
 
+	l.tokens = append(l.tokens, NewAtomVarToken(input[start:l.cursor]))
 	return nil
 }
 
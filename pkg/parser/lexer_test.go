@@ -42,10 +42,55 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			name:    "illegal char",
-			input:   "@",
+			input:   "#",
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name:    "number out of range",
+			input:   "1e400",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "imaginary number out of range",
+			input:   "2 * 1e400i",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:  "result references",
+			input: "$12 + ans*_",
+			want: []parser.Token{
+				parser.NewAtomVarToken("$12"),
+				parser.NewOPTokenByLiteral("+"),
+				parser.NewAtomVarToken("ans"),
+				parser.NewOPTokenByLiteral("*"),
+				parser.NewAtomVarToken("_"),
+			},
+			wantErr: false,
+		},
+		{
+			name:    "result reference without number",
+			input:   "$x",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:  "compound assignments",
+			input: "x **= 2*=y/=-1",
+			want: []parser.Token{
+				parser.NewAtomVarToken("x"),
+				parser.NewOPTokenByLiteral("**="),
+				parser.NewAtomNumToken("2"),
+				parser.NewOPTokenByLiteral("*="),
+				parser.NewAtomVarToken("y"),
+				parser.NewOPTokenByLiteral("/="),
+				parser.NewOPTokenByLiteral("-"),
+				parser.NewAtomNumToken("1"),
+			},
+			wantErr: false,
+		},
 		{
 			name:  "multiple operators",
 			input: "1 + 2 * 3 - 4 / 5",
@@ -445,6 +490,234 @@ func TestLexer(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:  "modulo, floor division and remainder",
+			input: "7 // 2 % 3 rem x",
+			want: []parser.Token{
+				parser.NewAtomNumToken("7"),
+				parser.NewOPTokenByLiteral("//"),
+				parser.NewAtomNumToken("2"),
+				parser.NewOPTokenByLiteral("%"),
+				parser.NewAtomNumToken("3"),
+				parser.NewOPTokenByLiteral("rem"),
+				parser.NewAtomVarToken("x"),
+			},
+			wantErr: false,
+		},
+		{
+			name:  "variable names contain remainder operator",
+			input: "remain - rem_1",
+			want: []parser.Token{
+				parser.NewAtomVarToken("remain"),
+				parser.NewOPTokenByLiteral("-"),
+				parser.NewAtomVarToken("rem_1"),
+			},
+			wantErr: false,
+		},
+		{
+			name:  "hexadecimal, binary and octal literals",
+			input: "0xFF + 0b1010 - 0o755",
+			want: []parser.Token{
+				parser.NewAtomNumToken("0xFF"),
+				parser.NewOPTokenByLiteral("+"),
+				parser.NewAtomNumToken("0b1010"),
+				parser.NewOPTokenByLiteral("-"),
+				parser.NewAtomNumToken("0o755"),
+			},
+			wantErr: false,
+		},
+		{
+			name:  "upper case base prefixes next to operators",
+			input: "(0XfF)*0B1**0O7",
+			want: []parser.Token{
+				parser.NewOPTokenByLiteral("("),
+				parser.NewAtomNumToken("0XfF"),
+				parser.NewOPTokenByLiteral(")"),
+				parser.NewOPTokenByLiteral("*"),
+				parser.NewAtomNumToken("0B1"),
+				parser.NewOPTokenByLiteral("**"),
+				parser.NewAtomNumToken("0O7"),
+			},
+			wantErr: false,
+		},
+		{
+			name:  "negative hexadecimal literal",
+			input: "-0x10",
+			want: []parser.Token{
+				parser.NewOPTokenByLiteral("-"),
+				parser.NewAtomNumToken("0x10"),
+			},
+			wantErr: false,
+		},
+		{
+			name:  "digit separators",
+			input: "1_000_000 / 0b1111_0000 + 3.141_592",
+			want: []parser.Token{
+				parser.NewAtomNumToken("1_000_000"),
+				parser.NewOPTokenByLiteral("/"),
+				parser.NewAtomNumToken("0b1111_0000"),
+				parser.NewOPTokenByLiteral("+"),
+				parser.NewAtomNumToken("3.141_592"),
+			},
+			wantErr: false,
+		},
+		{
+			name:  "zero followed by an operator",
+			input: "0-0x0",
+			want: []parser.Token{
+				parser.NewAtomNumToken("0"),
+				parser.NewOPTokenByLiteral("-"),
+				parser.NewAtomNumToken("0x0"),
+			},
+			wantErr: false,
+		},
+		{
+			name:    "invalid binary digit",
+			input:   "0b102",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "invalid octal digit",
+			input:   "0o8 + 1",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "invalid hexadecimal digit",
+			input:   "0xFG",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "missing digits after base prefix",
+			input:   "0x + 1",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "decimal point in hexadecimal literal",
+			input:   "0x1.8",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:  "hexadecimal literal beyond int64",
+			input: "0x1_0000_0000_0000_0000",
+			want: []parser.Token{
+				parser.NewAtomNumToken("0x1_0000_0000_0000_0000"),
+			},
+			wantErr: false,
+		},
+		{
+			name:    "consecutive digit separators",
+			input:   "1__000",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "trailing digit separator",
+			input:   "1000_ + 1",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "digit separator next to decimal point",
+			input:   "1_.5",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "trailing digit separator in binary literal",
+			input:   "0b1_",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:  "scientific notation",
+			input: "6.02e23 * 1.5E-9 / 1e+5",
+			want: []parser.Token{
+				parser.NewAtomNumToken("6.02e23"),
+				parser.NewOPTokenByLiteral("*"),
+				parser.NewAtomNumToken("1.5E-9"),
+				parser.NewOPTokenByLiteral("/"),
+				parser.NewAtomNumToken("1e+5"),
+			},
+			wantErr: false,
+		},
+		{
+			name:  "imaginary numbers",
+			input: "3 + 4i * 2.5j - 1e3i",
+			want: []parser.Token{
+				parser.NewAtomNumToken("3"),
+				parser.NewOPTokenByLiteral("+"),
+				parser.NewAtomNumToken("4i"),
+				parser.NewOPTokenByLiteral("*"),
+				parser.NewAtomNumToken("2.5j"),
+				parser.NewOPTokenByLiteral("-"),
+				parser.NewAtomNumToken("1e3i"),
+			},
+			wantErr: false,
+		},
+		{
+			name:  "units and conversion",
+			input: "5 km / 20min in km/h",
+			want: []parser.Token{
+				parser.NewAtomNumToken("5"),
+				parser.NewAtomVarToken("km"),
+				parser.NewOPTokenByLiteral("/"),
+				parser.NewAtomNumToken("20"),
+				parser.NewAtomVarToken("min"),
+				parser.NewOPTokenByLiteral("in"),
+				parser.NewAtomVarToken("km"),
+				parser.NewOPTokenByLiteral("/"),
+				parser.NewAtomVarToken("h"),
+			},
+			wantErr: false,
+		},
+		{
+			name:  "imaginary suffix only at the end of a word",
+			input: "2if + 3i_",
+			want: []parser.Token{
+				parser.NewAtomNumToken("2"),
+				parser.NewAtomVarToken("if"),
+				parser.NewOPTokenByLiteral("+"),
+				parser.NewAtomNumToken("3"),
+				parser.NewAtomVarToken("i_"),
+			},
+			wantErr: false,
+		},
+		{
+			name:  "scientific notation with leading decimal",
+			input: "-.5e-3",
+			want: []parser.Token{
+				parser.NewOPTokenByLiteral("-"),
+				parser.NewAtomNumToken(".5e-3"),
+			},
+			wantErr: false,
+		},
+		{
+			name:  "number followed by constant e",
+			input: "2e - e2",
+			want: []parser.Token{
+				parser.NewAtomNumToken("2"),
+				parser.NewAtomVarToken("e"),
+				parser.NewOPTokenByLiteral("-"),
+				parser.NewAtomVarToken("e2"),
+			},
+			wantErr: false,
+		},
+		{
+			name:  "exponent sign without digits",
+			input: "2e-x",
+			want: []parser.Token{
+				parser.NewAtomNumToken("2"),
+				parser.NewAtomVarToken("e"),
+				parser.NewOPTokenByLiteral("-"),
+				parser.NewAtomVarToken("x"),
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,135 @@
+package operator
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"simplecalc/pkg/parser/value"
+)
+
+// errInexact is returned from the rational implementation of an operator
+// if the result can't be exact, then the big float implementation is used.
+var errInexact = errors.New("inexact result")
+
+// arithmetic holds the implementations of an arithmetic operator for
+// each kind of numbers, the operands are promoted to the same kind first.
+// The complex and quantity implementations are optional for the operators
+// like "//", a number is a dimensionless quantity if the other one has unit.
+type arithmetic struct {
+	float    func(x, y float64) (float64, error)
+	rational func(x, y *big.Rat) (*big.Rat, error)
+	bigFloat func(z, x, y *big.Float) error
+	complex  func(x, y complex128) (complex128, error)
+	quantity func(x, y value.Quantity) (value.Value, error)
+}
+
+func evaluateArithmetic(literal string, oprands []value.Value, impl arithmetic) (result value.Value, err error) {
+	if len(oprands) != 2 {
+		return nil,
+			fmt.Errorf(
+				"%w: must have exactly 2 operands for '%s' operator",
+				ErrInvalidOperandCount,
+				literal)
+	}
+
+	if isList(oprands[0]) || isList(oprands[1]) {
+		return broadcast(literal, oprands, func(pair []value.Value) (value.Value, error) {
+			return evaluateArithmetic(literal, pair, impl)
+		})
+	}
+
+	if isQuantity(oprands[0]) || isQuantity(oprands[1]) {
+		return evaluateQuantity(literal, oprands[0], oprands[1], impl.quantity)
+	}
+
+	x, y, err := value.Promote(oprands[0], oprands[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: '%s' operator: %w", ErrInvalidOperand, literal, err)
+	}
+	digits := max(value.Digits(x), value.Digits(y))
+
+	switch x := x.(type) {
+	case value.Float:
+		r, err := impl.float(float64(x), float64(y.(value.Float)))
+		if err != nil {
+			return nil, err
+		}
+		return value.Float(r), nil
+	case value.Complex:
+		if impl.complex == nil {
+			return nil, fmt.Errorf("%w: '%s' operator doesn't support complex numbers", ErrInvalidOperand, literal)
+		}
+		r, err := impl.complex(complex128(x), complex128(y.(value.Complex)))
+		if err != nil {
+			return nil, err
+		}
+		return value.FromComplex(r), nil
+	case value.Rational:
+		r, err := impl.rational(x.Rat(), y.(value.Rational).Rat())
+		if err == nil {
+			return value.NewRational(r, digits), nil
+		}
+		if !errors.Is(err, errInexact) {
+			return nil, err
+		}
+	}
+
+	// big.Float panics with big.ErrNaN for the operations like inf - inf
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(big.ErrNaN); !ok {
+				panic(r)
+			}
+			result, err = nil, fmt.Errorf("'%s' operator: %w", literal, value.ErrNaN)
+		}
+	}()
+
+	bx, err := value.ToBigFloat(x, digits)
+	if err != nil {
+		return nil, err
+	}
+	by, err := value.ToBigFloat(y, digits)
+	if err != nil {
+		return nil, err
+	}
+	z := new(big.Float).SetPrec(value.PrecisionBits(digits))
+	if err := impl.bigFloat(z, bx, by); err != nil {
+		return nil, err
+	}
+
+	return value.NewBigFloat(z, digits), nil
+}
+
+// floorRat returns the greatest integer less than or equal to r
+func floorRat(r *big.Rat) *big.Rat {
+	// Euclidean division rounds down for the positive denominator
+	return new(big.Rat).SetInt(new(big.Int).Div(r.Num(), r.Denom()))
+}
+
+// truncRat returns the integer part of r
+func truncRat(r *big.Rat) *big.Rat {
+	return new(big.Rat).SetInt(new(big.Int).Quo(r.Num(), r.Denom()))
+}
+
+// floorBigFloat sets z to the greatest integer less than or equal to x
+func floorBigFloat(z, x *big.Float) {
+	if x.IsInf() {
+		z.Set(x)
+		return
+	}
+
+	r, _ := x.Rat(nil)
+	z.SetRat(floorRat(r))
+}
+
+// truncBigFloat sets z to the integer part of x
+func truncBigFloat(z, x *big.Float) {
+	if x.IsInf() {
+		z.Set(x)
+		return
+	}
+
+	i, _ := x.Int(nil)
+	z.SetInt(i)
+}
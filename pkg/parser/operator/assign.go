@@ -1,6 +1,11 @@
 package operator
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+
+	"simplecalc/pkg/parser/value"
+)
 
 type assign struct {
 	literal string
@@ -28,6 +33,10 @@ func (o *assign) IsPrefixOperator() bool {
 	return false
 }
 
+func (o *assign) IsPostfixOperator() bool {
+	return false
+}
+
 func (o *assign) isGroupingOperator() bool {
 	return false
 }
@@ -44,15 +53,108 @@ func (o *assign) GetPrefixBindingPower() (float32, error) {
 	return 0, fmt.Errorf("%w: '%s'", ErrNotPrefixOperator, o.literal)
 }
 
+func (o *assign) GetPostfixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPostfixOperator, o.literal)
+}
+
 func (o *assign) Lex(input *string, cursor int) (string, int) {
 	return o.literal, cursor + 1
 }
 
 // Evaluate is not applicable for Assign operator
-func (o *assign) Evaluate(oprands []float64) (float64, error) {
-	return 0, nil
+func (o *assign) Evaluate(oprands []value.Value) (value.Value, error) {
+	return value.Float(0), nil
 }
 
 func (o *assign) String() string {
 	return o.literal
 }
+
+// --------------------------------------------------------------
+
+// compoundAssign is the assignment with an arithmetic operator like "x += 1",
+// which is the same as "x = x + 1".
+type compoundAssign struct {
+	literal   string
+	operation string
+}
+
+func init() {
+	for _, operation := range []string{"+", "-", "*", "/", "**"} {
+		registerOperator(&compoundAssign{
+			literal:   operation + "=",
+			operation: operation,
+		})
+	}
+}
+
+// CompoundOperation returns the arithmetic operator of the compound assignment
+// like "+" of "+=", or false if the operator is not a compound assignment.
+func CompoundOperation(op Operator) (Operator, bool) {
+	compound, ok := op.(*compoundAssign)
+	if !ok {
+		return nil, false
+	}
+
+	return GetOperator(compound.operation), true
+}
+
+func (o *compoundAssign) Is(literal string) bool {
+	return o.literal == literal
+}
+
+func (o *compoundAssign) IsArithmeticOperator() bool {
+	return false
+}
+
+func (o *compoundAssign) IsInfixOperator() bool {
+	return true
+}
+
+func (o *compoundAssign) IsPrefixOperator() bool {
+	return false
+}
+
+func (o *compoundAssign) IsPostfixOperator() bool {
+	return false
+}
+
+func (o *compoundAssign) isGroupingOperator() bool {
+	return false
+}
+
+func (o *compoundAssign) GetLiteral() string {
+	return o.literal
+}
+
+func (o *compoundAssign) GetInfixBindingPower() (float32, float32, error) {
+	return 0.2, 0.1, nil
+}
+
+func (o *compoundAssign) GetPrefixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPrefixOperator, o.literal)
+}
+
+func (o *compoundAssign) GetPostfixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPostfixOperator, o.literal)
+}
+
+// Lex matches the whole literal, the longer literals like "**=" are
+// tried before "**" and "*", so "x **= 2" is not "x ** = 2".
+func (o *compoundAssign) Lex(input *string, cursor int) (string, int) {
+	if strings.HasPrefix((*input)[cursor:], o.literal) {
+		return o.literal, cursor + len(o.literal)
+	}
+
+	return "", cursor
+}
+
+// Evaluate is not applicable for the compound assignment,
+// which is replaced with the assignment before evaluation.
+func (o *compoundAssign) Evaluate(oprands []value.Value) (value.Value, error) {
+	return nil, fmt.Errorf("%w: '%s' must be replaced with assignment", ErrInvalidOperator, o.literal)
+}
+
+func (o *compoundAssign) String() string {
+	return o.literal
+}
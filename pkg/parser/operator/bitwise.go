@@ -0,0 +1,268 @@
+package operator
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"simplecalc/pkg/parser/value"
+)
+
+// bitwise operates on integers only, the binding powers follow the C precedence:
+// "|" < "^" < "&" < comparison < "<<" and ">>" < "+" and "-".
+type bitwise struct {
+	literal string
+	lBP     float32
+	operate func(x, y *big.Int) (*big.Int, error)
+}
+
+func init() {
+	registerOperator(&bitwise{
+		literal: "|",
+		lBP:     0.5,
+		operate: func(x, y *big.Int) (*big.Int, error) { return new(big.Int).Or(x, y), nil },
+	})
+	registerOperator(&bitwise{
+		literal: "^",
+		lBP:     0.55,
+		operate: func(x, y *big.Int) (*big.Int, error) { return new(big.Int).Xor(x, y), nil },
+	})
+	registerOperator(&bitwise{
+		literal: "&",
+		lBP:     0.6,
+		operate: func(x, y *big.Int) (*big.Int, error) { return new(big.Int).And(x, y), nil },
+	})
+	registerOperator(&bitwise{
+		literal: "<<",
+		lBP:     0.9,
+		operate: func(x, y *big.Int) (*big.Int, error) {
+			n, err := shiftCount(y)
+			if err != nil {
+				return nil, err
+			}
+			return new(big.Int).Lsh(x, n), nil
+		},
+	})
+	registerOperator(&bitwise{
+		literal: ">>",
+		lBP:     0.9,
+		operate: func(x, y *big.Int) (*big.Int, error) {
+			n, err := shiftCount(y)
+			if err != nil {
+				return nil, err
+			}
+			return new(big.Int).Rsh(x, n), nil
+		},
+	})
+}
+
+func (o *bitwise) Is(literal string) bool {
+	return o.literal == literal
+}
+
+func (o *bitwise) IsArithmeticOperator() bool {
+	return false
+}
+
+func (o *bitwise) IsInfixOperator() bool {
+	return true
+}
+
+func (o *bitwise) IsPrefixOperator() bool {
+	return false
+}
+
+func (o *bitwise) IsPostfixOperator() bool {
+	return false
+}
+
+func (o *bitwise) isGroupingOperator() bool {
+	return false
+}
+
+func (o *bitwise) GetLiteral() string {
+	return o.literal
+}
+
+func (o *bitwise) GetInfixBindingPower() (float32, float32, error) {
+	return o.lBP, o.lBP + 0.01, nil
+}
+
+func (o *bitwise) GetPrefixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPrefixOperator, o.literal)
+}
+
+func (o *bitwise) GetPostfixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPostfixOperator, o.literal)
+}
+
+func (o *bitwise) Lex(input *string, cursor int) (string, int) {
+	if len(o.literal) == 1 {
+		return o.literal, cursor + 1
+	}
+
+	if cursor < len(*input)-1 && (*input)[cursor+1] == o.literal[1] {
+		return o.literal, cursor + 2
+	}
+
+	return "", cursor
+}
+
+func (o *bitwise) Evaluate(oprands []value.Value) (value.Value, error) {
+	if len(oprands) != 2 {
+		return nil, fmt.Errorf("%w: must have exactly 2 operands for '%s' operator", ErrInvalidOperandCount, o.literal)
+	}
+
+	a, err := toInteger(o.literal, oprands[0])
+	if err != nil {
+		return nil, err
+	}
+	b, err := toInteger(o.literal, oprands[1])
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := o.operate(a, b)
+	if err != nil {
+		return nil, err
+	}
+
+	return integerResult(o.literal, oprands, result)
+}
+
+func (o *bitwise) String() string {
+	return o.literal
+}
+
+// --------------------------------------------------------------
+
+type bitwiseNot struct {
+	literal string
+}
+
+func init() {
+	registerOperator(&bitwiseNot{
+		literal: "~",
+	})
+}
+
+func (o *bitwiseNot) Is(literal string) bool {
+	return o.literal == literal
+}
+
+func (o *bitwiseNot) IsArithmeticOperator() bool {
+	return false
+}
+
+func (o *bitwiseNot) IsInfixOperator() bool {
+	return false
+}
+
+func (o *bitwiseNot) IsPrefixOperator() bool {
+	return true
+}
+
+func (o *bitwiseNot) IsPostfixOperator() bool {
+	return false
+}
+
+func (o *bitwiseNot) isGroupingOperator() bool {
+	return false
+}
+
+func (o *bitwiseNot) GetLiteral() string {
+	return o.literal
+}
+
+func (o *bitwiseNot) GetInfixBindingPower() (float32, float32, error) {
+	return 0, 0, fmt.Errorf("%w: '%s'", ErrNotInfixOperator, o.literal)
+}
+
+func (o *bitwiseNot) GetPrefixBindingPower() (float32, error) {
+	return 3.0, nil
+}
+
+func (o *bitwiseNot) GetPostfixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPostfixOperator, o.literal)
+}
+
+func (o *bitwiseNot) Lex(input *string, cursor int) (string, int) {
+	return o.literal, cursor + 1
+}
+
+// Evaluate inverts the bits of the right operand, the left operand
+// is the placeholder 0 added for the prefix operator
+func (o *bitwiseNot) Evaluate(oprands []value.Value) (value.Value, error) {
+	if len(oprands) != 2 {
+		return nil, fmt.Errorf("%w: must have exactly 2 operands for '%s' operator", ErrInvalidOperandCount, o.literal)
+	}
+
+	b, err := toInteger(o.literal, oprands[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return integerResult(o.literal, oprands[1:], new(big.Int).Not(b))
+}
+
+func (o *bitwiseNot) String() string {
+	return o.literal
+}
+
+// maxShiftCount is the largest shift count, so "1 << n" can't exhaust the memory
+const maxShiftCount = 1 << 16
+
+// shiftCount converts the right operand of a shift operator to the count of bits
+func shiftCount(y *big.Int) (uint, error) {
+	if y.Sign() < 0 || y.Cmp(big.NewInt(maxShiftCount)) > 0 {
+		return 0, fmt.Errorf("%w: shift count %s is not in [0, %d]", ErrInvalidOperand, y, maxShiftCount)
+	}
+
+	return uint(y.Uint64()), nil
+}
+
+// toInteger converts the operand of a bitwise operator to an integer,
+// the exact number must be integral and the float number must also be
+// exactly representable in float64.
+func toInteger(literal string, v value.Value) (*big.Int, error) {
+	switch v := v.(type) {
+	case value.Rational:
+		if v.Rat().IsInt() {
+			return new(big.Int).Set(v.Rat().Num()), nil
+		}
+	case value.BigFloat:
+		if v.Float().IsInt() {
+			n, _ := v.Float().Int(nil)
+			return n, nil
+		}
+	default:
+		x, err := value.ToFloat(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: operand of '%s' operator: %w", ErrInvalidOperand, literal, err)
+		}
+
+		const effectiveBoundary = float64(1 << 53)
+		if x == math.Trunc(x) && x > -effectiveBoundary && x < effectiveBoundary {
+			return big.NewInt(int64(x)), nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: '%s' operator requires integers, got %s", ErrInvalidOperand, literal, v)
+}
+
+// integerResult keeps the result exact if any of the operands is exact,
+// otherwise the result must be exactly representable in float64.
+func integerResult(literal string, oprands []value.Value, n *big.Int) (value.Value, error) {
+	for _, v := range oprands {
+		if v.Kind() == value.KindRational || v.Kind() == value.KindBigFloat {
+			return value.NewRational(new(big.Rat).SetInt(n), value.Digits(v)), nil
+		}
+	}
+
+	const effectiveBoundary = 1 << 53
+	if !n.IsInt64() || n.Int64() <= -effectiveBoundary || n.Int64() >= effectiveBoundary {
+		return nil, fmt.Errorf("%w: result of '%s' operator %s is out of range", ErrInvalidOperand, literal, n)
+	}
+
+	return value.Float(n.Int64()), nil
+}
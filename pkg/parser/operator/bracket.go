@@ -0,0 +1,139 @@
+package operator
+
+import (
+	"fmt"
+
+	"simplecalc/pkg/parser/value"
+)
+
+// leftBracket starts a list like "[1, 2]" as a prefix,
+// or the index of a list like "v[0]" as an infix.
+type leftBracket struct {
+	literal string
+}
+
+func init() {
+	registerOperator(&leftBracket{
+		literal: "[",
+	})
+}
+
+func (o *leftBracket) Is(literal string) bool {
+	return o.literal == literal
+}
+
+func (o *leftBracket) IsArithmeticOperator() bool {
+	return false
+}
+
+func (o *leftBracket) IsInfixOperator() bool {
+	return true
+}
+
+func (o *leftBracket) IsPrefixOperator() bool {
+	return false
+}
+
+func (o *leftBracket) IsPostfixOperator() bool {
+	return false
+}
+
+func (o *leftBracket) isGroupingOperator() bool {
+	return true
+}
+
+func (o *leftBracket) GetLiteral() string {
+	return o.literal
+}
+
+// GetInfixBindingPower returns the binding power of indexing,
+// which binds tighter than all other operators.
+func (o *leftBracket) GetInfixBindingPower() (float32, float32, error) {
+	return 5.0, 0, nil
+}
+
+func (o *leftBracket) GetPrefixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPrefixOperator, o.literal)
+}
+
+func (o *leftBracket) GetPostfixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPostfixOperator, o.literal)
+}
+
+func (o *leftBracket) Lex(input *string, cursor int) (string, int) {
+	return o.literal, cursor + 1
+}
+
+// Evaluate is not applicable for LeftBracket operator
+func (o *leftBracket) Evaluate(oprands []value.Value) (value.Value, error) {
+	return value.Float(0), nil
+}
+
+func (o *leftBracket) String() string {
+	return o.literal
+}
+
+// --------------------------------------------------------------
+
+type rightBracket struct {
+	literal string
+}
+
+func init() {
+	registerOperator(&rightBracket{
+		literal: "]",
+	})
+}
+
+func (o *rightBracket) Is(literal string) bool {
+	return o.literal == literal
+}
+
+func (o *rightBracket) IsArithmeticOperator() bool {
+	return false
+}
+
+func (o *rightBracket) IsInfixOperator() bool {
+	return false
+}
+
+func (o *rightBracket) IsPrefixOperator() bool {
+	return false
+}
+
+func (o *rightBracket) IsPostfixOperator() bool {
+	return false
+}
+
+func (o *rightBracket) isGroupingOperator() bool {
+	return true
+}
+
+func (o *rightBracket) GetLiteral() string {
+	return o.literal
+}
+
+func (o *rightBracket) GetInfixBindingPower() (float32, float32, error) {
+	return 0, 0, fmt.Errorf("%w: '%s'", ErrNotInfixOperator, o.literal)
+}
+
+func (o *rightBracket) GetPrefixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPrefixOperator, o.literal)
+}
+
+func (o *rightBracket) GetPostfixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPostfixOperator, o.literal)
+}
+
+func (o *rightBracket) Lex(input *string, cursor int) (string, int) {
+	return o.literal, cursor + 1
+}
+
+// Evaluate is not applicable for RightBracket operator
+func (o *rightBracket) Evaluate(oprands []value.Value) (value.Value, error) {
+	return value.Float(0), nil
+}
+
+func (o *rightBracket) String() string {
+	return o.literal
+}
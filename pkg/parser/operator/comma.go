@@ -0,0 +1,71 @@
+package operator
+
+import (
+	"fmt"
+
+	"simplecalc/pkg/parser/value"
+)
+
+// comma separates the arguments of a function call
+type comma struct {
+	literal string
+}
+
+func init() {
+	registerOperator(&comma{
+		literal: ",",
+	})
+}
+
+func (o *comma) Is(literal string) bool {
+	return o.literal == literal
+}
+
+func (o *comma) IsArithmeticOperator() bool {
+	return false
+}
+
+func (o *comma) IsInfixOperator() bool {
+	return false
+}
+
+func (o *comma) IsPrefixOperator() bool {
+	return false
+}
+
+func (o *comma) IsPostfixOperator() bool {
+	return false
+}
+
+func (o *comma) isGroupingOperator() bool {
+	return true
+}
+
+func (o *comma) GetLiteral() string {
+	return o.literal
+}
+
+func (o *comma) GetInfixBindingPower() (float32, float32, error) {
+	return 0, 0, fmt.Errorf("%w: '%s'", ErrNotInfixOperator, o.literal)
+}
+
+func (o *comma) GetPrefixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPrefixOperator, o.literal)
+}
+
+func (o *comma) GetPostfixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPostfixOperator, o.literal)
+}
+
+func (o *comma) Lex(input *string, cursor int) (string, int) {
+	return o.literal, cursor + 1
+}
+
+// Evaluate is not applicable for Comma operator
+func (o *comma) Evaluate(oprands []value.Value) (value.Value, error) {
+	return value.Float(0), nil
+}
+
+func (o *comma) String() string {
+	return o.literal
+}
@@ -0,0 +1,150 @@
+package operator
+
+import (
+	"fmt"
+
+	"simplecalc/pkg/parser/value"
+)
+
+// comparison compares two numbers and returns a boolean,
+// "==" and "!=" bind looser than "<", "<=", ">" and ">=" like C.
+type comparison struct {
+	literal string
+	lBP     float32
+	compare func(x, y float64) bool
+}
+
+func init() {
+	registerOperator(&comparison{
+		literal: "==",
+		lBP:     0.7,
+		compare: func(x, y float64) bool { return x == y },
+	})
+	registerOperator(&comparison{
+		literal: "!=",
+		lBP:     0.7,
+		compare: func(x, y float64) bool { return x != y },
+	})
+	registerOperator(&comparison{
+		literal: "<",
+		lBP:     0.8,
+		compare: func(x, y float64) bool { return x < y },
+	})
+	registerOperator(&comparison{
+		literal: "<=",
+		lBP:     0.8,
+		compare: func(x, y float64) bool { return x <= y },
+	})
+	registerOperator(&comparison{
+		literal: ">",
+		lBP:     0.8,
+		compare: func(x, y float64) bool { return x > y },
+	})
+	registerOperator(&comparison{
+		literal: ">=",
+		lBP:     0.8,
+		compare: func(x, y float64) bool { return x >= y },
+	})
+}
+
+func (o *comparison) Is(literal string) bool {
+	return o.literal == literal
+}
+
+func (o *comparison) IsArithmeticOperator() bool {
+	return false
+}
+
+func (o *comparison) IsInfixOperator() bool {
+	return true
+}
+
+func (o *comparison) IsPrefixOperator() bool {
+	return false
+}
+
+func (o *comparison) IsPostfixOperator() bool {
+	return false
+}
+
+func (o *comparison) isGroupingOperator() bool {
+	return false
+}
+
+func (o *comparison) GetLiteral() string {
+	return o.literal
+}
+
+func (o *comparison) GetInfixBindingPower() (float32, float32, error) {
+	return o.lBP, o.lBP + 0.01, nil
+}
+
+func (o *comparison) GetPrefixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPrefixOperator, o.literal)
+}
+
+func (o *comparison) GetPostfixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPostfixOperator, o.literal)
+}
+
+func (o *comparison) Lex(input *string, cursor int) (string, int) {
+	if len(o.literal) == 1 {
+		return o.literal, cursor + 1
+	}
+
+	if cursor < len(*input)-1 && (*input)[cursor+1] == o.literal[1] {
+		return o.literal, cursor + 2
+	}
+
+	return "", cursor
+}
+
+func (o *comparison) Evaluate(oprands []value.Value) (value.Value, error) {
+	if len(oprands) != 2 {
+		return nil,
+			fmt.Errorf(
+				"%w: must have exactly 2 operands for '%s' operator",
+				ErrInvalidOperandCount,
+				o.literal)
+	}
+
+	// Compare the amounts of the quantities in the same unit
+	if isQuantity(oprands[0]) || isQuantity(oprands[1]) {
+		return evaluateQuantity(o.literal, oprands[0], oprands[1], func(x, y value.Quantity) (value.Value, error) {
+			a, b, _, err := alignQuantities(x, y)
+			if err != nil {
+				return nil, err
+			}
+			return value.Bool(o.compare(a, b)), nil
+		})
+	}
+
+	x, y, err := value.Promote(oprands[0], oprands[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: '%s' operator: %w", ErrInvalidOperand, o.literal, err)
+	}
+
+	// Complex numbers can only be equal or not
+	if x, ok := x.(value.Complex); ok {
+		if !o.Is("==") && !o.Is("!=") {
+			return nil, fmt.Errorf("%w: '%s' operator: complex numbers are not ordered", ErrInvalidOperand, o.literal)
+		}
+		return value.Bool((x == y.(value.Complex)) == o.Is("==")), nil
+	}
+
+	// Compare float64 directly to keep the semantics of NaN
+	if x, ok := x.(value.Float); ok {
+		return value.Bool(o.compare(float64(x), float64(y.(value.Float)))), nil
+	}
+
+	c, err := value.Compare(x, y)
+	if err != nil {
+		return nil, fmt.Errorf("%w: '%s' operator: %w", ErrInvalidOperand, o.literal, err)
+	}
+
+	return value.Bool(o.compare(float64(c), 0)), nil
+}
+
+func (o *comparison) String() string {
+	return o.literal
+}
@@ -0,0 +1,142 @@
+package operator
+
+import (
+	"fmt"
+
+	"simplecalc/pkg/parser/value"
+)
+
+// question starts a conditional expression like "c ? a : b", it's parsed
+// as a mixfix construct in the expression parser instead of a plain infix
+// operator to evaluate only the selected branch.
+type question struct {
+	literal string
+}
+
+func init() {
+	registerOperator(&question{
+		literal: "?",
+	})
+}
+
+func (o *question) Is(literal string) bool {
+	return o.literal == literal
+}
+
+func (o *question) IsArithmeticOperator() bool {
+	return false
+}
+
+func (o *question) IsInfixOperator() bool {
+	return true
+}
+
+func (o *question) IsPrefixOperator() bool {
+	return false
+}
+
+func (o *question) IsPostfixOperator() bool {
+	return false
+}
+
+func (o *question) isGroupingOperator() bool {
+	return false
+}
+
+func (o *question) GetLiteral() string {
+	return o.literal
+}
+
+// GetInfixBindingPower returns a lower right binding power
+// to make the conditional expression right associative
+func (o *question) GetInfixBindingPower() (float32, float32, error) {
+	return 0.25, 0.24, nil
+}
+
+func (o *question) GetPrefixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPrefixOperator, o.literal)
+}
+
+func (o *question) GetPostfixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPostfixOperator, o.literal)
+}
+
+func (o *question) Lex(input *string, cursor int) (string, int) {
+	return o.literal, cursor + 1
+}
+
+// Evaluate is not applicable for Question operator,
+// the branches are evaluated by the expression lazily
+func (o *question) Evaluate(oprands []value.Value) (value.Value, error) {
+	return value.Float(0), nil
+}
+
+func (o *question) String() string {
+	return o.literal
+}
+
+// --------------------------------------------------------------
+
+// colon separates the branches of a conditional expression
+type colon struct {
+	literal string
+}
+
+func init() {
+	registerOperator(&colon{
+		literal: ":",
+	})
+}
+
+func (o *colon) Is(literal string) bool {
+	return o.literal == literal
+}
+
+func (o *colon) IsArithmeticOperator() bool {
+	return false
+}
+
+func (o *colon) IsInfixOperator() bool {
+	return false
+}
+
+func (o *colon) IsPrefixOperator() bool {
+	return false
+}
+
+func (o *colon) IsPostfixOperator() bool {
+	return false
+}
+
+func (o *colon) isGroupingOperator() bool {
+	return true
+}
+
+func (o *colon) GetLiteral() string {
+	return o.literal
+}
+
+func (o *colon) GetInfixBindingPower() (float32, float32, error) {
+	return 0, 0, fmt.Errorf("%w: '%s'", ErrNotInfixOperator, o.literal)
+}
+
+func (o *colon) GetPrefixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPrefixOperator, o.literal)
+}
+
+func (o *colon) GetPostfixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPostfixOperator, o.literal)
+}
+
+func (o *colon) Lex(input *string, cursor int) (string, int) {
+	return o.literal, cursor + 1
+}
+
+// Evaluate is not applicable for Colon operator
+func (o *colon) Evaluate(oprands []value.Value) (value.Value, error) {
+	return value.Float(0), nil
+}
+
+func (o *colon) String() string {
+	return o.literal
+}
@@ -0,0 +1,106 @@
+package operator
+
+import (
+	"fmt"
+	"math"
+
+	"simplecalc/pkg/parser/unit"
+	"simplecalc/pkg/parser/value"
+)
+
+// conversion converts the left operand to the unit of the right operand
+// like "5 km / 20 min in km/h", the right operand must be a unit without a number.
+// It binds looser than all other operators except "?" and "=".
+type conversion struct {
+	literal string
+}
+
+// unitAmountTolerance allows the rounding errors of the units like "km * h / h"
+const unitAmountTolerance = 1e-12
+
+func init() {
+	registerOperator(&conversion{
+		literal: "in",
+	})
+	registerOperator(&conversion{
+		literal: "to",
+	})
+}
+
+func (o *conversion) Is(literal string) bool {
+	return o.literal == literal
+}
+
+func (o *conversion) IsArithmeticOperator() bool {
+	return false
+}
+
+func (o *conversion) IsInfixOperator() bool {
+	return true
+}
+
+func (o *conversion) IsPrefixOperator() bool {
+	return false
+}
+
+func (o *conversion) IsPostfixOperator() bool {
+	return false
+}
+
+func (o *conversion) isGroupingOperator() bool {
+	return false
+}
+
+func (o *conversion) GetLiteral() string {
+	return o.literal
+}
+
+func (o *conversion) GetInfixBindingPower() (float32, float32, error) {
+	return 0.27, 0.28, nil
+}
+
+func (o *conversion) GetPrefixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPrefixOperator, o.literal)
+}
+
+func (o *conversion) GetPostfixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPostfixOperator, o.literal)
+}
+
+func (o *conversion) Lex(input *string, cursor int) (string, int) {
+	return lexKeyword(o.literal, input, cursor)
+}
+
+func (o *conversion) Evaluate(oprands []value.Value) (value.Value, error) {
+	if len(oprands) != 2 {
+		return nil,
+			fmt.Errorf(
+				"%w: must have exactly 2 operands for '%s' operator",
+				ErrInvalidOperandCount,
+				o.literal)
+	}
+
+	target, ok := oprands[1].(value.Quantity)
+	if !ok {
+		return nil, fmt.Errorf("%w: right operand of '%s' operator must be a unit, got '%s'", ErrInvalidOperand, o.literal, oprands[1])
+	}
+	// The target like "2 km" would silently drop the 2
+	if math.Abs(target.Amount()-1) > unitAmountTolerance {
+		return nil, fmt.Errorf("%w: right operand of '%s' operator must be a unit without a number, got '%s'", ErrInvalidOperand, o.literal, target)
+	}
+	q, err := value.ToQuantity(oprands[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: left operand of '%s' operator: %w", ErrInvalidOperand, o.literal, err)
+	}
+
+	amount, err := unit.Convert(q.Amount(), q.Unit(), target.Unit())
+	if err != nil {
+		return nil, err
+	}
+
+	return value.NewQuantity(amount, target.Unit()), nil
+}
+
+func (o *conversion) String() string {
+	return o.literal
+}
@@ -1,6 +1,11 @@
 package operator
 
-import "fmt"
+import (
+	"fmt"
+	"math/big"
+
+	"simplecalc/pkg/parser/value"
+)
 
 type divide struct {
 	literal string
@@ -28,6 +33,10 @@ func (o *divide) IsPrefixOperator() bool {
 	return false
 }
 
+func (o *divide) IsPostfixOperator() bool {
+	return false
+}
+
 func (o *divide) isGroupingOperator() bool {
 	return false
 }
@@ -44,24 +53,48 @@ func (o *divide) GetPrefixBindingPower() (float32, error) {
 	return 0, fmt.Errorf("%w: '%s'", ErrNotPrefixOperator, o.literal)
 }
 
+func (o *divide) GetPostfixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPostfixOperator, o.literal)
+}
+
 func (o *divide) Lex(input *string, cursor int) (string, int) {
 	return o.literal, cursor + 1
 }
 
-func (o *divide) Evaluate(oprands []float64) (float64, error) {
-	if len(oprands) != 2 {
-		return 0,
-			fmt.Errorf(
-				"%w: must have exactly 2 operands for '%s' operator",
-				ErrInvalidOperandCount,
-				o.literal)
-	}
-
-	if oprands[1] == 0 {
-		return 0, ErrDivisionByZero
-	}
-
-	return oprands[0] / oprands[1], nil
+func (o *divide) Evaluate(oprands []value.Value) (value.Value, error) {
+	return evaluateArithmetic(o.literal, oprands, arithmetic{
+		float: func(x, y float64) (float64, error) {
+			if y == 0 {
+				return 0, ErrDivisionByZero
+			}
+			return x / y, nil
+		},
+		rational: func(x, y *big.Rat) (*big.Rat, error) {
+			if y.Sign() == 0 {
+				return nil, ErrDivisionByZero
+			}
+			return new(big.Rat).Quo(x, y), nil
+		},
+		bigFloat: func(z, x, y *big.Float) error {
+			if y.Sign() == 0 {
+				return ErrDivisionByZero
+			}
+			z.Quo(x, y)
+			return nil
+		},
+		complex: func(x, y complex128) (complex128, error) {
+			if y == 0 {
+				return 0, ErrDivisionByZero
+			}
+			return x / y, nil
+		},
+		quantity: func(x, y value.Quantity) (value.Value, error) {
+			if y.Amount() == 0 {
+				return nil, ErrDivisionByZero
+			}
+			return value.NewQuantity(x.Amount()/y.Amount(), x.Unit().Div(y.Unit())), nil
+		},
+	})
 }
 
 func (o *divide) String() string {
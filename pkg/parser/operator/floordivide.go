@@ -0,0 +1,95 @@
+package operator
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"simplecalc/pkg/parser/value"
+)
+
+type floorDivide struct {
+	literal string
+}
+
+func init() {
+	registerOperator(&floorDivide{
+		literal: "//",
+	})
+}
+
+func (o *floorDivide) Is(literal string) bool {
+	return o.literal == literal
+}
+
+func (o *floorDivide) IsArithmeticOperator() bool {
+	return true
+}
+
+func (o *floorDivide) IsInfixOperator() bool {
+	return true
+}
+
+func (o *floorDivide) IsPrefixOperator() bool {
+	return false
+}
+
+func (o *floorDivide) IsPostfixOperator() bool {
+	return false
+}
+
+func (o *floorDivide) isGroupingOperator() bool {
+	return false
+}
+
+func (o *floorDivide) GetLiteral() string {
+	return o.literal
+}
+
+func (o *floorDivide) GetInfixBindingPower() (float32, float32, error) {
+	return 2.0, 2.1, nil
+}
+
+func (o *floorDivide) GetPrefixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPrefixOperator, o.literal)
+}
+
+func (o *floorDivide) GetPostfixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPostfixOperator, o.literal)
+}
+
+func (o *floorDivide) Lex(input *string, cursor int) (string, int) {
+	if cursor < len(*input)-1 && (*input)[cursor+1] == '/' {
+		return o.literal, cursor + 2
+	}
+
+	return "", cursor
+}
+
+func (o *floorDivide) Evaluate(oprands []value.Value) (value.Value, error) {
+	return evaluateArithmetic(o.literal, oprands, arithmetic{
+		float: func(x, y float64) (float64, error) {
+			if y == 0 {
+				return 0, ErrDivisionByZero
+			}
+			return math.Floor(x / y), nil
+		},
+		rational: func(x, y *big.Rat) (*big.Rat, error) {
+			if y.Sign() == 0 {
+				return nil, ErrDivisionByZero
+			}
+			return floorRat(new(big.Rat).Quo(x, y)), nil
+		},
+		bigFloat: func(z, x, y *big.Float) error {
+			if y.Sign() == 0 {
+				return ErrDivisionByZero
+			}
+			floorBigFloat(z, z.Quo(x, y))
+			return nil
+		},
+	})
+}
+
+func (o *floorDivide) String() string {
+	return o.literal
+}
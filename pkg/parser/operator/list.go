@@ -0,0 +1,45 @@
+package operator
+
+import (
+	"fmt"
+
+	"simplecalc/pkg/parser/value"
+)
+
+func isList(v value.Value) bool {
+	return v.Kind() == value.KindList
+}
+
+// broadcast evaluates the operator for each pair of the elements
+// of two lists with the same length, or for each element of the list
+// with the other operand like [1, 2] * 2.
+func broadcast(literal string, oprands []value.Value, eval func([]value.Value) (value.Value, error)) (value.Value, error) {
+	x, y := oprands[0], oprands[1]
+	lx, xIsList := x.(value.List)
+	ly, yIsList := y.(value.List)
+
+	n := len(lx)
+	if !xIsList {
+		n = len(ly)
+	} else if yIsList && len(ly) != n {
+		return nil, fmt.Errorf("%w: '%s' operator with lists of %d and %d elements", value.ErrLengthMismatch, literal, len(lx), len(ly))
+	}
+
+	result := make(value.List, 0, n)
+	for i := range n {
+		if xIsList {
+			x = lx[i]
+		}
+		if yIsList {
+			y = ly[i]
+		}
+
+		elem, err := eval([]value.Value{x, y})
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, elem)
+	}
+
+	return result, nil
+}
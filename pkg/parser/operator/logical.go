@@ -0,0 +1,241 @@
+package operator
+
+import (
+	"fmt"
+
+	"simplecalc/pkg/parser/value"
+)
+
+// Logical operators treat any number other than 0 as true,
+// "&&" and "||" are short-circuited by the expression evaluator.
+
+type and struct {
+	literal string
+}
+
+func init() {
+	registerOperator(&and{
+		literal: "&&",
+	})
+}
+
+func (o *and) Is(literal string) bool {
+	return o.literal == literal
+}
+
+func (o *and) IsArithmeticOperator() bool {
+	return false
+}
+
+func (o *and) IsInfixOperator() bool {
+	return true
+}
+
+func (o *and) IsPrefixOperator() bool {
+	return false
+}
+
+func (o *and) IsPostfixOperator() bool {
+	return false
+}
+
+func (o *and) isGroupingOperator() bool {
+	return false
+}
+
+func (o *and) GetLiteral() string {
+	return o.literal
+}
+
+func (o *and) GetInfixBindingPower() (float32, float32, error) {
+	return 0.4, 0.41, nil
+}
+
+func (o *and) GetPrefixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPrefixOperator, o.literal)
+}
+
+func (o *and) GetPostfixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPostfixOperator, o.literal)
+}
+
+func (o *and) Lex(input *string, cursor int) (string, int) {
+	if cursor < len(*input)-1 && (*input)[cursor+1] == '&' {
+		return o.literal, cursor + 2
+	}
+
+	return "", cursor
+}
+
+func (o *and) Evaluate(oprands []value.Value) (value.Value, error) {
+	if len(oprands) != 2 {
+		return nil,
+			fmt.Errorf(
+				"%w: must have exactly 2 operands for '%s' operator",
+				ErrInvalidOperandCount,
+				o.literal)
+	}
+
+	return value.Bool(value.Truthy(oprands[0]) && value.Truthy(oprands[1])), nil
+}
+
+func (o *and) String() string {
+	return o.literal
+}
+
+// --------------------------------------------------------------
+
+type or struct {
+	literal string
+}
+
+func init() {
+	registerOperator(&or{
+		literal: "||",
+	})
+}
+
+func (o *or) Is(literal string) bool {
+	return o.literal == literal
+}
+
+func (o *or) IsArithmeticOperator() bool {
+	return false
+}
+
+func (o *or) IsInfixOperator() bool {
+	return true
+}
+
+func (o *or) IsPrefixOperator() bool {
+	return false
+}
+
+func (o *or) IsPostfixOperator() bool {
+	return false
+}
+
+func (o *or) isGroupingOperator() bool {
+	return false
+}
+
+func (o *or) GetLiteral() string {
+	return o.literal
+}
+
+func (o *or) GetInfixBindingPower() (float32, float32, error) {
+	return 0.3, 0.31, nil
+}
+
+func (o *or) GetPrefixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPrefixOperator, o.literal)
+}
+
+func (o *or) GetPostfixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPostfixOperator, o.literal)
+}
+
+func (o *or) Lex(input *string, cursor int) (string, int) {
+	if cursor < len(*input)-1 && (*input)[cursor+1] == '|' {
+		return o.literal, cursor + 2
+	}
+
+	return "", cursor
+}
+
+func (o *or) Evaluate(oprands []value.Value) (value.Value, error) {
+	if len(oprands) != 2 {
+		return nil,
+			fmt.Errorf(
+				"%w: must have exactly 2 operands for '%s' operator",
+				ErrInvalidOperandCount,
+				o.literal)
+	}
+
+	return value.Bool(value.Truthy(oprands[0]) || value.Truthy(oprands[1])), nil
+}
+
+func (o *or) String() string {
+	return o.literal
+}
+
+// --------------------------------------------------------------
+
+type not struct {
+	literal string
+}
+
+func init() {
+	registerOperator(&not{
+		literal: "!",
+	})
+}
+
+func (o *not) Is(literal string) bool {
+	return o.literal == literal
+}
+
+func (o *not) IsArithmeticOperator() bool {
+	return false
+}
+
+func (o *not) IsInfixOperator() bool {
+	return false
+}
+
+func (o *not) IsPrefixOperator() bool {
+	return true
+}
+
+// IsPostfixOperator returns true for the factorial like "5!"
+func (o *not) IsPostfixOperator() bool {
+	return true
+}
+
+func (o *not) isGroupingOperator() bool {
+	return false
+}
+
+func (o *not) GetLiteral() string {
+	return o.literal
+}
+
+func (o *not) GetInfixBindingPower() (float32, float32, error) {
+	return 0, 0, fmt.Errorf("%w: '%s'", ErrNotInfixOperator, o.literal)
+}
+
+func (o *not) GetPrefixBindingPower() (float32, error) {
+	return 3.0, nil
+}
+
+// GetPostfixBindingPower returns the binding power of the factorial, which binds
+// tighter than power and prefix operators, so "2 ** 3!" is 64 and "-3!" is -6.
+func (o *not) GetPostfixBindingPower() (float32, error) {
+	return 4.5, nil
+}
+
+func (o *not) Lex(input *string, cursor int) (string, int) {
+	return o.literal, cursor + 1
+}
+
+// Evaluate negates the right operand, the left operand
+// is the placeholder 0 added for the prefix operator,
+// or calculates the factorial of the only operand of the postfix operator.
+func (o *not) Evaluate(oprands []value.Value) (value.Value, error) {
+	if len(oprands) == 1 {
+		return evaluatePostfix(oprands[0], factorial)
+	}
+	if len(oprands) != 2 {
+		return nil,
+			fmt.Errorf(
+				"%w: must have exactly 2 operands for '%s' operator",
+				ErrInvalidOperandCount,
+				o.literal)
+	}
+
+	return value.Bool(!value.Truthy(oprands[1])), nil
+}
+
+func (o *not) String() string {
+	return o.literal
+}
@@ -0,0 +1,146 @@
+package operator
+
+import (
+	"fmt"
+
+	"simplecalc/pkg/parser/value"
+)
+
+// matMul is the matrix multiplication like [[1, 2], [3, 4]] @ [[5], [6]],
+// a list of numbers is a row vector on the left and a column vector on the right,
+// so the product of a matrix and a vector is a vector and of two vectors is a number.
+type matMul struct {
+	literal string
+}
+
+func init() {
+	registerOperator(&matMul{
+		literal: "@",
+	})
+}
+
+func (o *matMul) Is(literal string) bool {
+	return o.literal == literal
+}
+
+func (o *matMul) IsArithmeticOperator() bool {
+	return true
+}
+
+func (o *matMul) IsInfixOperator() bool {
+	return true
+}
+
+func (o *matMul) IsPrefixOperator() bool {
+	return false
+}
+
+func (o *matMul) IsPostfixOperator() bool {
+	return false
+}
+
+func (o *matMul) isGroupingOperator() bool {
+	return false
+}
+
+func (o *matMul) GetLiteral() string {
+	return o.literal
+}
+
+func (o *matMul) GetInfixBindingPower() (float32, float32, error) {
+	return 2.0, 2.1, nil
+}
+
+func (o *matMul) GetPrefixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPrefixOperator, o.literal)
+}
+
+func (o *matMul) GetPostfixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPostfixOperator, o.literal)
+}
+
+func (o *matMul) Lex(input *string, cursor int) (string, int) {
+	return o.literal, cursor + 1
+}
+
+func (o *matMul) Evaluate(oprands []value.Value) (value.Value, error) {
+	if len(oprands) != 2 {
+		return nil, fmt.Errorf(
+			"%w: must have exactly 2 operands for '%s' operator",
+			ErrInvalidOperandCount,
+			o.literal)
+	}
+
+	x, err := value.ToMatrix(oprands[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: left operand of '%s' operator: %w", ErrInvalidOperand, o.literal, err)
+	}
+	y, err := value.ToMatrix(oprands[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: right operand of '%s' operator: %w", ErrInvalidOperand, o.literal, err)
+	}
+
+	// A vector on the right is a column
+	xIsVector, yIsVector := isVector(oprands[0]), isVector(oprands[1])
+	if yIsVector {
+		y = y.Transpose()
+	}
+
+	product, err := multiplyMatrices(x, y)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case xIsVector && yIsVector:
+		return product[0][0], nil
+	case xIsVector:
+		return value.List(product[0]), nil
+	case yIsVector:
+		return value.List(product.Transpose()[0]), nil
+	default:
+		return product.List(), nil
+	}
+}
+
+func (o *matMul) String() string {
+	return o.literal
+}
+
+// multiplyMatrices multiplies two matrices with the "+" and "*" operators,
+// so the exact numbers, the complex numbers and the units are kept.
+func multiplyMatrices(x, y value.Matrix) (value.Matrix, error) {
+	if x.Shape().Cols != y.Shape().Rows {
+		return nil, &value.ShapeError{Op: "@", Shapes: []value.Shape{x.Shape(), y.Shape()}}
+	}
+
+	add, mul := GetOperator("+"), GetOperator("*")
+	product := make(value.Matrix, 0, len(x))
+	for i := range x {
+		row := make([]value.Value, 0, len(y[0]))
+		for j := range y[0] {
+			var sum value.Value
+			for k := range y {
+				elem, err := mul.Evaluate([]value.Value{x[i][k], y[k][j]})
+				if err != nil {
+					return nil, err
+				}
+				if sum == nil {
+					sum = elem
+				} else if sum, err = add.Evaluate([]value.Value{sum, elem}); err != nil {
+					return nil, err
+				}
+			}
+			row = append(row, sum)
+		}
+		product = append(product, row)
+	}
+
+	return product, nil
+}
+
+// isVector checks if the value is a list of numbers rather than a list of rows
+func isVector(v value.Value) bool {
+	list, ok := v.(value.List)
+	return ok && len(list) > 0 && !isList(list[0])
+}
@@ -1,6 +1,12 @@
 package operator
 
-import "fmt"
+import (
+	"fmt"
+	"math/big"
+
+	"simplecalc/pkg/parser/value"
+)
+
 type minus struct {
 	literal string
 }
@@ -27,6 +33,10 @@ func (o *minus) IsPrefixOperator() bool {
 	return true
 }
 
+func (o *minus) IsPostfixOperator() bool {
+	return false
+}
+
 func (o *minus) isGroupingOperator() bool {
 	return false
 }
@@ -43,20 +53,33 @@ func (o *minus) GetPrefixBindingPower() (float32, error) {
 	return 3.0, nil
 }
 
+func (o *minus) GetPostfixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPostfixOperator, o.literal)
+}
+
 func (o *minus) Lex(input *string, cursor int) (string, int) {
 	return o.literal, cursor + 1
 }
 
-func (o *minus) Evaluate(oprands []float64) (float64, error) {
-	if len(oprands) != 2 {
-		return 0,
-			fmt.Errorf(
-				"%w: must have exactly 2 operands for '%s' operator",
-				ErrInvalidOperandCount,
-				o.literal)
-	}
-
-	return oprands[0] - oprands[1], nil
+func (o *minus) Evaluate(oprands []value.Value) (value.Value, error) {
+	return evaluateArithmetic(o.literal, oprands, arithmetic{
+		float: func(x, y float64) (float64, error) {
+			return x - y, nil
+		},
+		rational: func(x, y *big.Rat) (*big.Rat, error) {
+			return new(big.Rat).Sub(x, y), nil
+		},
+		bigFloat: func(z, x, y *big.Float) error {
+			z.Sub(x, y)
+			return nil
+		},
+		complex: func(x, y complex128) (complex128, error) {
+			return x - y, nil
+		},
+		quantity: func(x, y value.Quantity) (value.Value, error) {
+			return sumQuantities(x, y, -1)
+		},
+	})
 }
 
 func (o *minus) String() string {
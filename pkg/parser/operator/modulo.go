@@ -0,0 +1,112 @@
+package operator
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"simplecalc/pkg/parser/value"
+)
+
+type modulo struct {
+	literal string
+}
+
+func init() {
+	registerOperator(&modulo{
+		literal: "%",
+	})
+}
+
+func (o *modulo) Is(literal string) bool {
+	return o.literal == literal
+}
+
+func (o *modulo) IsArithmeticOperator() bool {
+	return true
+}
+
+func (o *modulo) IsInfixOperator() bool {
+	return true
+}
+
+func (o *modulo) IsPrefixOperator() bool {
+	return false
+}
+
+// IsPostfixOperator returns true for the percent like "15%"
+func (o *modulo) IsPostfixOperator() bool {
+	return true
+}
+
+func (o *modulo) isGroupingOperator() bool {
+	return false
+}
+
+func (o *modulo) GetLiteral() string {
+	return o.literal
+}
+
+func (o *modulo) GetInfixBindingPower() (float32, float32, error) {
+	return 2.0, 2.1, nil
+}
+
+func (o *modulo) GetPrefixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPrefixOperator, o.literal)
+}
+
+// GetPostfixBindingPower returns the binding power of the percent,
+// which is the same as the factorial.
+func (o *modulo) GetPostfixBindingPower() (float32, error) {
+	return 4.5, nil
+}
+
+func (o *modulo) Lex(input *string, cursor int) (string, int) {
+	return o.literal, cursor + 1
+}
+
+// Evaluate calculates the modulo of two operands,
+// or the percent of the only operand of the postfix operator.
+func (o *modulo) Evaluate(oprands []value.Value) (value.Value, error) {
+	if len(oprands) == 1 {
+		return evaluatePostfix(oprands[0], percent)
+	}
+
+	return evaluateArithmetic(o.literal, oprands, arithmetic{
+		float: func(x, y float64) (float64, error) {
+			if y == 0 {
+				return 0, ErrDivisionByZero
+			}
+
+			// Floor semantics, the result has the same sign as the divisor
+			result := math.Mod(x, y)
+			if result != 0 && (result < 0) != (y < 0) {
+				result += y
+			}
+			return result, nil
+		},
+		rational: func(x, y *big.Rat) (*big.Rat, error) {
+			if y.Sign() == 0 {
+				return nil, ErrDivisionByZero
+			}
+
+			// x - y * floor(x / y)
+			q := floorRat(new(big.Rat).Quo(x, y))
+			return new(big.Rat).Sub(x, q.Mul(q, y)), nil
+		},
+		bigFloat: func(z, x, y *big.Float) error {
+			if y.Sign() == 0 {
+				return ErrDivisionByZero
+			}
+
+			q := new(big.Float).SetPrec(z.Prec())
+			floorBigFloat(q, q.Quo(x, y))
+			z.Sub(x, q.Mul(q, y))
+			return nil
+		},
+	})
+}
+
+func (o *modulo) String() string {
+	return o.literal
+}
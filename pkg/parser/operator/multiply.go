@@ -1,6 +1,11 @@
 package operator
 
-import "fmt"
+import (
+	"fmt"
+	"math/big"
+
+	"simplecalc/pkg/parser/value"
+)
 
 type multiply struct {
 	literal string
@@ -28,6 +33,10 @@ func (o *multiply) IsPrefixOperator() bool {
 	return false
 }
 
+func (o *multiply) IsPostfixOperator() bool {
+	return false
+}
+
 func (o *multiply) isGroupingOperator() bool {
 	return false
 }
@@ -44,20 +53,33 @@ func (o *multiply) GetPrefixBindingPower() (float32, error) {
 	return 0, fmt.Errorf("%w: '%s'", ErrNotPrefixOperator, o.literal)
 }
 
+func (o *multiply) GetPostfixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPostfixOperator, o.literal)
+}
+
 func (o *multiply) Lex(input *string, cursor int) (string, int) {
 	return o.literal, cursor + 1
 }
 
-func (o *multiply) Evaluate(oprands []float64) (float64, error) {
-	if len(oprands) != 2 {
-		return 0,
-			fmt.Errorf(
-				"%w: must have exactly 2 operands for '%s' operator",
-				ErrInvalidOperandCount,
-				o.literal)
-	}
-
-	return oprands[0] * oprands[1], nil
+func (o *multiply) Evaluate(oprands []value.Value) (value.Value, error) {
+	return evaluateArithmetic(o.literal, oprands, arithmetic{
+		float: func(x, y float64) (float64, error) {
+			return x * y, nil
+		},
+		rational: func(x, y *big.Rat) (*big.Rat, error) {
+			return new(big.Rat).Mul(x, y), nil
+		},
+		bigFloat: func(z, x, y *big.Float) error {
+			z.Mul(x, y)
+			return nil
+		},
+		complex: func(x, y complex128) (complex128, error) {
+			return x * y, nil
+		},
+		quantity: func(x, y value.Quantity) (value.Value, error) {
+			return value.NewQuantity(x.Amount()*y.Amount(), x.Unit().Mul(y.Unit())), nil
+		},
+	})
 }
 
 func (o *multiply) String() string {
@@ -2,6 +2,10 @@ package operator
 
 import (
 	"fmt"
+	"slices"
+	"unicode"
+
+	"simplecalc/pkg/parser/value"
 )
 
 var (
@@ -12,6 +16,7 @@ var (
 	ErrInvalidOperandCount = fmt.Errorf("invalid operand count")
 	ErrNotInfixOperator    = fmt.Errorf("not infix operator")
 	ErrNotPrefixOperator   = fmt.Errorf("not prefix operator")
+	ErrNotPostfixOperator  = fmt.Errorf("not postfix operator")
 )
 
 type OPid uint8
@@ -21,19 +26,21 @@ type Operator interface {
 	IsArithmeticOperator() bool
 	IsInfixOperator() bool
 	IsPrefixOperator() bool
+	IsPostfixOperator() bool
 	isGroupingOperator() bool
 	GetLiteral() string
 	GetInfixBindingPower() (float32, float32, error)
 	GetPrefixBindingPower() (float32, error)
+	GetPostfixBindingPower() (float32, error)
 	Lex(input *string, cursor int) (string, int)
-	Evaluate(oprands []float64) (float64, error)
+	Evaluate(oprands []value.Value) (value.Value, error)
 	String() string
 }
 
 // set containers that registers all operators from init
 var (
 	allOperators = map[string]Operator{}
-	lexOperators = map[byte]map[int]Operator{}
+	lexOperators = map[byte][]Operator{}
 )
 
 func registerOperator(op Operator) {
@@ -46,16 +53,17 @@ func registerOperator(op Operator) {
 	if len(opLiteral) == 0 {
 		panic("operator literal cannot be empty")
 	}
+	// Keep operators with the same first character sorted by
+	// the length of their literals in descending order for longest-match
 	opStartWith := opLiteral[0]
-	if _, ok := lexOperators[opStartWith]; !ok {
-		lexOperators[opStartWith] = make(map[int]Operator)
-	}
-	lexOperators[opStartWith][len(opLiteral)] = op
+	lexOperators[opStartWith] = append(lexOperators[opStartWith], op)
+	slices.SortStableFunc(lexOperators[opStartWith], func(a, b Operator) int {
+		return len(b.GetLiteral()) - len(a.GetLiteral())
+	})
 }
 
 // LexWithOperator lexes the input string and returns the operator and the new cursor position
-// It expects the input string to not contain any whitespace characters!
-// It won't check and panic with whitespace for performance
+// It tries the longest operator first, so "**" is matched before "*"
 func LexWithOperator(input *string, cursor int) (Operator, int) {
 	if cursor >= len(*input) {
 		return nil, cursor
@@ -63,13 +71,10 @@ func LexWithOperator(input *string, cursor int) (Operator, int) {
 
 	char := (*input)[cursor]
 	if ops, ok := lexOperators[char]; ok {
-		for i := len(ops); i > 0; i-- {
-			if op, ok := ops[i]; ok {
-				token, newCursor := op.Lex(input, cursor)
-				if token != "" {
-					return op, newCursor
-				}
-				continue
+		for _, op := range ops {
+			token, newCursor := op.Lex(input, cursor)
+			if token != "" {
+				return op, newCursor
 			}
 		}
 		// No operator found
@@ -81,6 +86,47 @@ func LexWithOperator(input *string, cursor int) (Operator, int) {
 	}
 }
 
+// lexKeyword lexes an operator spelled as a word like "rem", it only matches
+// when the word is not a part of a longer name like "remain" or "rem2".
+func lexKeyword(keyword string, input *string, cursor int) (string, int) {
+	end := cursor + len(keyword)
+	if end > len(*input) || (*input)[cursor:end] != keyword {
+		return "", cursor
+	}
+
+	if end < len(*input) {
+		next := rune((*input)[end])
+		if next == '_' || unicode.IsLetter(next) || unicode.IsDigit(next) {
+			return "", cursor
+		}
+	}
+
+	return keyword, end
+}
+
+// binaryOperands checks there are exactly 2 operands
+// for the operator and converts them to float64
+func binaryOperands(literal string, oprands []value.Value) (float64, float64, error) {
+	if len(oprands) != 2 {
+		return 0, 0,
+			fmt.Errorf(
+				"%w: must have exactly 2 operands for '%s' operator",
+				ErrInvalidOperandCount,
+				literal)
+	}
+
+	x, err := value.ToFloat(oprands[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: left operand of '%s' operator: %w", ErrInvalidOperand, literal, err)
+	}
+	y, err := value.ToFloat(oprands[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: right operand of '%s' operator: %w", ErrInvalidOperand, literal, err)
+	}
+
+	return x, y, nil
+}
+
 func GetOperator(literal string) Operator {
 	if op, ok := allOperators[literal]; ok {
 		return op
@@ -75,6 +75,237 @@ func TestLexWithOperator(t *testing.T) {
 				newCursor: 3,
 			},
 		},
+		{
+			name: "handle modulo operator from simple expression",
+			input: input{
+				input:  "7%2",
+				cursor: 1,
+			},
+			want: output{
+				Operator:  op.GetOperator("%"),
+				newCursor: 2,
+			},
+		},
+		{
+			name: "handle matrix multiplication operator",
+			input: input{
+				input:  "A@B",
+				cursor: 1,
+			},
+			want: output{
+				Operator:  op.GetOperator("@"),
+				newCursor: 2,
+			},
+		},
+		{
+			name: "handle compound assignment before power operator",
+			input: input{
+				input:  "x**=2",
+				cursor: 1,
+			},
+			want: output{
+				Operator:  op.GetOperator("**="),
+				newCursor: 4,
+			},
+		},
+		{
+			name: "handle power operator before compound assignment",
+			input: input{
+				input:  "x**2",
+				cursor: 1,
+			},
+			want: output{
+				Operator:  op.GetOperator("**"),
+				newCursor: 3,
+			},
+		},
+		{
+			name: "handle floor division operator before divide operator",
+			input: input{
+				input:  "7//2",
+				cursor: 1,
+			},
+			want: output{
+				Operator:  op.GetOperator("//"),
+				newCursor: 3,
+			},
+		},
+		{
+			name: "handle remainder operator surrounded by spaces",
+			input: input{
+				input:  "7 rem 2",
+				cursor: 2,
+			},
+			want: output{
+				Operator:  op.GetOperator("rem"),
+				newCursor: 5,
+			},
+		},
+		{
+			name: "handle remainder operator followed by a number",
+			input: input{
+				input:  "7 rem2",
+				cursor: 2,
+			},
+			want: output{
+				Operator:  nil,
+				newCursor: 2,
+			},
+		},
+		{
+			name: "handle variable starts with remainder operator",
+			input: input{
+				input:  "remain",
+				cursor: 0,
+			},
+			want: output{
+				Operator:  nil,
+				newCursor: 0,
+			},
+		},
+		{
+			name: "handle conversion operator",
+			input: input{
+				input:  "1 mi in km",
+				cursor: 5,
+			},
+			want: output{
+				Operator:  op.GetOperator("in"),
+				newCursor: 7,
+			},
+		},
+		{
+			name: "handle conversion operator alias",
+			input: input{
+				input:  "1 mi to km",
+				cursor: 5,
+			},
+			want: output{
+				Operator:  op.GetOperator("to"),
+				newCursor: 7,
+			},
+		},
+		{
+			name: "handle unit starts with conversion operator",
+			input: input{
+				input:  "inch",
+				cursor: 0,
+			},
+			want: output{
+				Operator:  nil,
+				newCursor: 0,
+			},
+		},
+		{
+			name: "handle equal operator before assign operator",
+			input: input{
+				input:  "a==b",
+				cursor: 1,
+			},
+			want: output{
+				Operator:  op.GetOperator("=="),
+				newCursor: 3,
+			},
+		},
+		{
+			name: "handle not equal operator before not operator",
+			input: input{
+				input:  "a!=b",
+				cursor: 1,
+			},
+			want: output{
+				Operator:  op.GetOperator("!="),
+				newCursor: 3,
+			},
+		},
+		{
+			name: "handle not operator",
+			input: input{
+				input:  "!a",
+				cursor: 0,
+			},
+			want: output{
+				Operator:  op.GetOperator("!"),
+				newCursor: 1,
+			},
+		},
+		{
+			name: "handle less than or equal operator",
+			input: input{
+				input:  "a<=b",
+				cursor: 1,
+			},
+			want: output{
+				Operator:  op.GetOperator("<="),
+				newCursor: 3,
+			},
+		},
+		{
+			name: "handle greater than operator",
+			input: input{
+				input:  "a>b",
+				cursor: 1,
+			},
+			want: output{
+				Operator:  op.GetOperator(">"),
+				newCursor: 2,
+			},
+		},
+		{
+			name: "handle logical operators",
+			input: input{
+				input:  "a&&b||c",
+				cursor: 1,
+			},
+			want: output{
+				Operator:  op.GetOperator("&&"),
+				newCursor: 3,
+			},
+		},
+		{
+			name: "handle bitwise and operator after logical and operator",
+			input: input{
+				input:  "a&b",
+				cursor: 1,
+			},
+			want: output{
+				Operator:  op.GetOperator("&"),
+				newCursor: 2,
+			},
+		},
+		{
+			name: "handle left shift operator before less than or equal operator",
+			input: input{
+				input:  "a<<b",
+				cursor: 1,
+			},
+			want: output{
+				Operator:  op.GetOperator("<<"),
+				newCursor: 3,
+			},
+		},
+		{
+			name: "handle right shift operator before greater than operator",
+			input: input{
+				input:  "a>>b",
+				cursor: 1,
+			},
+			want: output{
+				Operator:  op.GetOperator(">>"),
+				newCursor: 3,
+			},
+		},
+		{
+			name: "handle bitwise xor operator",
+			input: input{
+				input:  "a^b",
+				cursor: 1,
+			},
+			want: output{
+				Operator:  op.GetOperator("^"),
+				newCursor: 2,
+			},
+		},
 		{
 			name: "handle number from single digit",
 			input: input{
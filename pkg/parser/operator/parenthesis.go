@@ -1,6 +1,10 @@
 package operator
 
-import "fmt"
+import (
+	"fmt"
+
+	"simplecalc/pkg/parser/value"
+)
 
 type leftParen struct {
 	literal string
@@ -28,6 +32,10 @@ func (o *leftParen) IsPrefixOperator() bool {
 	return false
 }
 
+func (o *leftParen) IsPostfixOperator() bool {
+	return false
+}
+
 func (o *leftParen) isGroupingOperator() bool {
 	return true
 }
@@ -44,13 +52,17 @@ func (o *leftParen) GetPrefixBindingPower() (float32, error) {
 	return 0, fmt.Errorf("%w: '%s'", ErrNotPrefixOperator, o.literal)
 }
 
+func (o *leftParen) GetPostfixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPostfixOperator, o.literal)
+}
+
 func (o *leftParen) Lex(input *string, cursor int) (string, int) {
 	return o.literal, cursor + 1
 }
 
 // Evaluate is not applicable for LeftParen operator
-func (o *leftParen) Evaluate(oprands []float64) (float64, error) {
-	return 0, nil
+func (o *leftParen) Evaluate(oprands []value.Value) (value.Value, error) {
+	return value.Float(0), nil
 }
 
 func (o *leftParen) String() string {
@@ -85,6 +97,10 @@ func (o *rightParen) IsPrefixOperator() bool {
 	return false
 }
 
+func (o *rightParen) IsPostfixOperator() bool {
+	return false
+}
+
 func (o *rightParen) isGroupingOperator() bool {
 	return true
 }
@@ -101,13 +117,17 @@ func (o *rightParen) GetPrefixBindingPower() (float32, error) {
 	return 0, fmt.Errorf("%w: '%s'", ErrNotPrefixOperator, o.literal)
 }
 
+func (o *rightParen) GetPostfixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPostfixOperator, o.literal)
+}
+
 func (o *rightParen) Lex(input *string, cursor int) (string, int) {
 	return o.literal, cursor + 1
 }
 
 // Evaluate is not applicable for RightParen operator
-func (o *rightParen) Evaluate(oprands []float64) (float64, error) {
-	return 0, nil
+func (o *rightParen) Evaluate(oprands []value.Value) (value.Value, error) {
+	return value.Float(0), nil
 }
 
 func (o *rightParen) String() string {
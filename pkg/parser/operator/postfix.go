@@ -0,0 +1,77 @@
+package operator
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"simplecalc/pkg/parser/value"
+)
+
+// maxExactFactorial limits the integer for exact factorials,
+// the larger ones are calculated with float64.
+const maxExactFactorial = 10000
+
+// evaluatePostfix applies the postfix operator to the operand,
+// or to each element of the list like [3, 4]! is [6, 24].
+func evaluatePostfix(oprand value.Value, eval func(value.Value) (value.Value, error)) (value.Value, error) {
+	list, ok := oprand.(value.List)
+	if !ok {
+		return eval(oprand)
+	}
+
+	result := make(value.List, 0, len(list))
+	for _, elem := range list {
+		v, err := evaluatePostfix(elem, eval)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, v)
+	}
+
+	return result, nil
+}
+
+// factorial calculates n! of the non-negative integers exactly in the exact modes,
+// and the gamma function of x + 1 for the other numbers like 0.5!.
+func factorial(v value.Value) (value.Value, error) {
+	if r, ok := v.(value.Rational); ok && r.Rat().IsInt() {
+		n := r.Rat().Num()
+		if n.Sign() < 0 {
+			return nil, fmt.Errorf("%w: factorial of negative integer %s", ErrInvalidOperand, n)
+		}
+		if n.IsInt64() && n.Int64() <= maxExactFactorial {
+			result := new(big.Int).MulRange(1, n.Int64())
+			return value.NewRational(new(big.Rat).SetInt(result), value.Digits(v)), nil
+		}
+	}
+
+	x, err := value.ToFloat(v)
+	if err != nil {
+		return nil, fmt.Errorf("%w: operand of '!' operator: %w", ErrInvalidOperand, err)
+	}
+	if x < 0 && x == math.Trunc(x) {
+		return nil, fmt.Errorf("%w: factorial of negative integer %g", ErrInvalidOperand, x)
+	}
+
+	result := math.Gamma(x + 1)
+	if math.IsInf(result, 0) {
+		return nil, fmt.Errorf("%w: factorial of %g is too large", ErrInvalidOperand, x)
+	}
+	if digits := value.Digits(v); digits > 0 {
+		return value.NewBigFloatFromFloat(result, digits), nil
+	}
+
+	return value.Float(result), nil
+}
+
+// percent divides the operand by 100 like 15% is 0.15,
+// so "200 * 15%" is 30.
+func percent(v value.Value) (value.Value, error) {
+	var hundred value.Value = value.Float(100)
+	if digits := value.Digits(v); digits > 0 {
+		hundred = value.NewRational(big.NewRat(100, 1), digits)
+	}
+
+	return GetOperator("/").Evaluate([]value.Value{v, hundred})
+}
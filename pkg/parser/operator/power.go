@@ -3,6 +3,10 @@ package operator
 import (
 	"fmt"
 	"math"
+	"math/big"
+	"math/cmplx"
+
+	"simplecalc/pkg/parser/value"
 )
 
 type power struct {
@@ -31,6 +35,10 @@ func (o *power) IsPrefixOperator() bool {
 	return false
 }
 
+func (o *power) IsPostfixOperator() bool {
+	return false
+}
+
 func (o *power) isGroupingOperator() bool {
 	return false
 }
@@ -47,6 +55,10 @@ func (o *power) GetPrefixBindingPower() (float32, error) {
 	return 0, fmt.Errorf("%w: '%s'", ErrNotPrefixOperator, o.literal)
 }
 
+func (o *power) GetPostfixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPostfixOperator, o.literal)
+}
+
 func (o *power) Lex(input *string, cursor int) (string, int) {
 	if cursor < len(*input)-1 && (*input)[cursor+1] == '*' {
 		return o.literal, cursor + 2
@@ -55,18 +67,165 @@ func (o *power) Lex(input *string, cursor int) (string, int) {
 	return "", cursor
 }
 
-func (o *power) Evaluate(oprands []float64) (float64, error) {
-	if len(oprands) != 2 {
-		return 0,
-			fmt.Errorf(
-				"%w: must have exactly 2 operands for '%s' operator",
-				ErrInvalidOperandCount,
-				o.literal)
+func (o *power) Evaluate(oprands []value.Value) (value.Value, error) {
+	if len(oprands) == 2 && (isList(oprands[0]) || isList(oprands[1])) {
+		return broadcast(o.literal, oprands, o.Evaluate)
+	}
+
+	// A negative base with a fractional exponent has a complex result
+	if x, y, ok := negativeBaseFraction(oprands); ok {
+		return value.FromComplex(cmplx.Pow(complex(x, 0), complex(y, 0))), nil
 	}
 
-	return math.Pow(oprands[0], oprands[1]), nil
+	return evaluateArithmetic(o.literal, oprands, arithmetic{
+		float: func(x, y float64) (float64, error) {
+			return math.Pow(x, y), nil
+		},
+		rational: func(x, y *big.Rat) (*big.Rat, error) {
+			// Only integer exponents keep the result exact
+			if !y.IsInt() || !y.Num().IsInt64() {
+				return nil, errInexact
+			}
+			n := y.Num().Int64()
+			if n > maxExactExponent || n < -maxExactExponent {
+				return nil, errInexact
+			}
+			if n < 0 {
+				if x.Sign() == 0 {
+					return nil, ErrDivisionByZero
+				}
+				x, n = new(big.Rat).Inv(x), -n
+			}
+
+			exp := big.NewInt(n)
+			num := new(big.Int).Exp(x.Num(), exp, nil)
+			denom := new(big.Int).Exp(x.Denom(), exp, nil)
+			return new(big.Rat).SetFrac(num, denom), nil
+		},
+		bigFloat: powBigFloat,
+		complex: func(x, y complex128) (complex128, error) {
+			if x == 0 && real(y) < 0 {
+				return 0, ErrDivisionByZero
+			}
+
+			// Multiply for integer exponents to avoid the rounding errors
+			// of cmplx.Pow, so (2i) ** 2 is exactly -4
+			if n := real(y); imag(y) == 0 && n == math.Trunc(n) && math.Abs(n) <= maxExactExponent {
+				result := complex(1, 0)
+				for base, e := x, int(math.Abs(n)); e > 0; e >>= 1 {
+					if e&1 == 1 {
+						result *= base
+					}
+					base *= base
+				}
+				if n < 0 {
+					result = 1 / result
+				}
+				return result, nil
+			}
+
+			return cmplx.Pow(x, y), nil
+		},
+		quantity: powQuantity,
+	})
 }
 
 func (o *power) String() string {
 	return o.literal
 }
+
+// maxExactExponent limits the integer exponent for exact results
+// to avoid the numbers with too many digits
+const maxExactExponent = 10000
+
+// powBigFloat raises x to the power of y, the result is only as precise
+// as float64 if y is neither an integer nor 0.5. It returns an error
+// instead of the infinity or 0 if the result is out of range.
+func powBigFloat(z, x, y *big.Float) error {
+	if y.IsInt() {
+		n, acc := y.Int64()
+		if acc == big.Exact && n != math.MinInt64 {
+			// Exponentiation by squaring at the precision of the result
+			base := new(big.Float).SetPrec(z.Prec()).Set(x)
+			result := new(big.Float).SetPrec(z.Prec()).SetInt64(1)
+			for e := max(n, -n); e > 0; e >>= 1 {
+				if e&1 == 1 {
+					result.Mul(result, base)
+				}
+				if e > 1 {
+					base.Mul(base, base)
+				}
+			}
+			if isPowOutOfRange(x, y, result) {
+				return fmt.Errorf("%w: result of '%s' operator is out of range", ErrInvalidOperand, "**")
+			}
+			if n < 0 {
+				if result.Sign() == 0 {
+					return ErrDivisionByZero
+				}
+				result.Quo(new(big.Float).SetInt64(1), result)
+			}
+			z.Set(result)
+			return nil
+		}
+	}
+
+	if y.Cmp(big.NewFloat(0.5)) == 0 && x.Sign() >= 0 {
+		z.Sqrt(x)
+		return nil
+	}
+
+	fx, _ := x.Float64()
+	fy, _ := y.Float64()
+	result := math.Pow(fx, fy)
+	if math.IsNaN(result) {
+		return fmt.Errorf("'%s' operator: %w", "**", value.ErrNaN)
+	}
+	if isPowOutOfRange(x, y, big.NewFloat(result)) {
+		return fmt.Errorf("%w: result of '%s' operator is out of range", ErrInvalidOperand, "**")
+	}
+	// Only the digits supported by float64 are displayed
+	z.SetPrec(53).SetFloat64(result)
+	return nil
+}
+
+// maxExactBits limits the binary exponent of the exact power,
+// the numbers beyond it take too long to display in decimal
+const maxExactBits = 1 << 16
+
+// isPowOutOfRange checks if the power of the finite x and y overflows
+// to the infinity, underflows to 0 while x is not 0, or is too large
+// or too small to display
+func isPowOutOfRange(x, y, result *big.Float) bool {
+	if x.IsInf() || y.IsInf() {
+		return false
+	}
+	if result.IsInf() {
+		return true
+	}
+	if result.Sign() == 0 {
+		return x.Sign() != 0
+	}
+
+	exp := result.MantExp(nil)
+	return exp > maxExactBits || exp < -maxExactBits
+}
+
+// negativeBaseFraction checks if the real base is negative and
+// the real exponent is fractional like (-8) ** (1/3)
+func negativeBaseFraction(oprands []value.Value) (float64, float64, bool) {
+	if len(oprands) != 2 {
+		return 0, 0, false
+	}
+
+	x, err := value.ToFloat(oprands[0])
+	if err != nil || x >= 0 {
+		return 0, 0, false
+	}
+	y, err := value.ToFloat(oprands[1])
+	if err != nil || math.IsInf(y, 0) || y == math.Trunc(y) {
+		return 0, 0, false
+	}
+
+	return x, y, true
+}
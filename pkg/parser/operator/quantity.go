@@ -0,0 +1,76 @@
+package operator
+
+import (
+	"fmt"
+	"math"
+
+	"simplecalc/pkg/parser/unit"
+	"simplecalc/pkg/parser/value"
+)
+
+func isQuantity(v value.Value) bool {
+	return v.Kind() == value.KindQuantity
+}
+
+func evaluateQuantity(literal string, x, y value.Value, impl func(x, y value.Quantity) (value.Value, error)) (value.Value, error) {
+	if impl == nil {
+		return nil, fmt.Errorf("%w: '%s' operator doesn't support units", ErrInvalidOperand, literal)
+	}
+
+	qx, err := value.ToQuantity(x)
+	if err != nil {
+		return nil, fmt.Errorf("%w: left operand of '%s' operator: %w", ErrInvalidOperand, literal, err)
+	}
+	qy, err := value.ToQuantity(y)
+	if err != nil {
+		return nil, fmt.Errorf("%w: right operand of '%s' operator: %w", ErrInvalidOperand, literal, err)
+	}
+
+	return impl(qx, qy)
+}
+
+// alignQuantities returns the amounts of the quantities in the same unit,
+// a number 0 has any unit so "-x" as "0 - x" works for the quantities.
+func alignQuantities(x, y value.Quantity) (float64, float64, unit.Unit, error) {
+	if isPlainZero(x) {
+		return 0, y.Amount(), y.Unit(), nil
+	}
+	if isPlainZero(y) {
+		return x.Amount(), 0, x.Unit(), nil
+	}
+
+	amount, err := unit.Convert(y.Amount(), y.Unit(), x.Unit())
+	if err != nil {
+		return 0, 0, unit.Unit{}, err
+	}
+
+	return x.Amount(), amount, x.Unit(), nil
+}
+
+func isPlainZero(q value.Quantity) bool {
+	return q.Amount() == 0 && q.Unit().IsDimensionless()
+}
+
+// sumQuantities adds or subtracts the quantities in the unit of x
+func sumQuantities(x, y value.Quantity, sign float64) (value.Value, error) {
+	a, b, u, err := alignQuantities(x, y)
+	if err != nil {
+		return nil, err
+	}
+
+	return value.NewQuantity(a+sign*b, u), nil
+}
+
+// powQuantity raises the quantity to an integer power like (3 m) ** 2
+func powQuantity(x, y value.Quantity) (value.Value, error) {
+	if !y.Unit().IsDimensionless() {
+		return nil, fmt.Errorf("%w: '**' operator requires a number as exponent, got '%s'", ErrInvalidOperand, y)
+	}
+
+	n := y.Amount() * y.Unit().Factor()
+	if n != math.Trunc(n) || math.Abs(n) > maxExactExponent {
+		return nil, fmt.Errorf("%w: '**' operator requires an integer exponent for units, got %g", ErrInvalidOperand, n)
+	}
+
+	return value.NewQuantity(math.Pow(x.Amount(), n), x.Unit().Pow(int(n))), nil
+}
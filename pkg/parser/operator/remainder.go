@@ -0,0 +1,99 @@
+package operator
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"simplecalc/pkg/parser/value"
+)
+
+type remainder struct {
+	literal string
+}
+
+func init() {
+	registerOperator(&remainder{
+		literal: "rem",
+	})
+}
+
+func (o *remainder) Is(literal string) bool {
+	return o.literal == literal
+}
+
+func (o *remainder) IsArithmeticOperator() bool {
+	return true
+}
+
+func (o *remainder) IsInfixOperator() bool {
+	return true
+}
+
+func (o *remainder) IsPrefixOperator() bool {
+	return false
+}
+
+func (o *remainder) IsPostfixOperator() bool {
+	return false
+}
+
+func (o *remainder) isGroupingOperator() bool {
+	return false
+}
+
+func (o *remainder) GetLiteral() string {
+	return o.literal
+}
+
+func (o *remainder) GetInfixBindingPower() (float32, float32, error) {
+	return 2.0, 2.1, nil
+}
+
+func (o *remainder) GetPrefixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPrefixOperator, o.literal)
+}
+
+func (o *remainder) GetPostfixBindingPower() (float32, error) {
+	return 0, fmt.Errorf("%w: '%s'", ErrNotPostfixOperator, o.literal)
+}
+
+func (o *remainder) Lex(input *string, cursor int) (string, int) {
+	return lexKeyword(o.literal, input, cursor)
+}
+
+func (o *remainder) Evaluate(oprands []value.Value) (value.Value, error) {
+	return evaluateArithmetic(o.literal, oprands, arithmetic{
+		float: func(x, y float64) (float64, error) {
+			if y == 0 {
+				return 0, ErrDivisionByZero
+			}
+
+			// Truncated semantics, the result has the same sign as the dividend
+			return math.Mod(x, y), nil
+		},
+		rational: func(x, y *big.Rat) (*big.Rat, error) {
+			if y.Sign() == 0 {
+				return nil, ErrDivisionByZero
+			}
+
+			// x - y * trunc(x / y)
+			q := truncRat(new(big.Rat).Quo(x, y))
+			return new(big.Rat).Sub(x, q.Mul(q, y)), nil
+		},
+		bigFloat: func(z, x, y *big.Float) error {
+			if y.Sign() == 0 {
+				return ErrDivisionByZero
+			}
+
+			q := new(big.Float).SetPrec(z.Prec())
+			truncBigFloat(q, q.Quo(x, y))
+			z.Sub(x, q.Mul(q, y))
+			return nil
+		},
+	})
+}
+
+func (o *remainder) String() string {
+	return o.literal
+}
@@ -4,24 +4,231 @@ import (
 	"fmt"
 	"math"
 	"os"
-	"strconv"
+	"slices"
 	"strings"
+
+	"simplecalc/pkg/parser/value"
 )
 
 const IntApproxTolerance = 1e-10
 
+// MaxPrecision is the maximum number of significant decimal digits
+// of the exact mode, limited by the digits of the constants.
+const MaxPrecision = 100
+
+var (
+	ErrInvalidAssignment    = fmt.Errorf("can only assign to a variable or a function")
+	ErrInvalidPrecision     = fmt.Errorf("invalid precision")
+	ErrInvalidMode          = fmt.Errorf("invalid mode")
+	ErrInvalidJuxtaposition = fmt.Errorf("invalid precedence of implicit multiplication")
+)
+
+// Mode is how the numbers are evaluated
+type Mode uint8
+
+const (
+	// ModeFloat evaluates the numbers with float64
+	ModeFloat Mode = iota
+	// ModeExact evaluates the numbers with math/big, the results are decimals
+	ModeExact
+	// ModeRational evaluates the numbers like ModeExact,
+	// the results are reduced fractions like 1/2
+	ModeRational
+)
+
+func (m Mode) String() string {
+	switch m {
+	case ModeFloat:
+		return "float"
+	case ModeExact:
+		return "exact"
+	case ModeRational:
+		return "rational"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseMode returns the mode with the name from Mode.String
+func ParseMode(name string) (Mode, error) {
+	for _, m := range []Mode{ModeFloat, ModeExact, ModeRational} {
+		if m.String() == name {
+			return m, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: '%s', must be float, exact or rational", ErrInvalidMode, name)
+}
+
+// Format returns how the results of the mode are displayed by default
+func (m Mode) Format() value.Format {
+	if m == ModeRational {
+		return value.FormatFraction
+	}
+
+	return value.FormatDecimal
+}
+
+// Juxtaposition is the precedence of the implicit multiplication like "2x"
+type Juxtaposition uint8
+
+const (
+	// JuxtapositionEqual binds "2x" as tight as "*", so "1 / 2x" is (1 / 2) * x
+	JuxtapositionEqual Juxtaposition = iota
+	// JuxtapositionTight binds "2x" tighter than "/", so "1 / 2x" is 1 / (2 * x)
+	JuxtapositionTight
+)
+
+func (j Juxtaposition) String() string {
+	switch j {
+	case JuxtapositionEqual:
+		return "equal"
+	case JuxtapositionTight:
+		return "tight"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseJuxtaposition returns the precedence with the name from Juxtaposition.String
+func ParseJuxtaposition(name string) (Juxtaposition, error) {
+	for _, j := range []Juxtaposition{JuxtapositionEqual, JuxtapositionTight} {
+		if j.String() == name {
+			return j, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: '%s', must be equal or tight", ErrInvalidJuxtaposition, name)
+}
+
+// bindingPower returns the left and right binding powers of the implicit
+// multiplication, the tight ones are between "/" and the prefix operators.
+func (j Juxtaposition) bindingPower() (float32, float32) {
+	if j == JuxtapositionTight {
+		return 2.2, 2.3
+	}
+
+	return 2.0, 2.1
+}
+
 type Parser struct {
-	variables map[string]float64
+	variables map[string]value.Value
+	functions map[string]*userFunction
+
+	mode Mode
+	// digits is the number of significant decimal digits
+	// to approximate the results of the exact modes
+	digits uint
+	// juxtaposition is the precedence of the implicit multiplication
+	juxtaposition Juxtaposition
+	// echo shows the values of the assignments like "x = 5" as the results
+	echo bool
+	// results are all the results numbered from 1 for "$n"
+	results []value.Value
 }
 
 func NewParser() *Parser {
 	return &Parser{
-		variables: make(map[string]float64),
+		variables: make(map[string]value.Value),
+		functions: make(map[string]*userFunction),
+		digits:    value.DefaultDigits,
 	}
 }
 
-func (p *Parser) Parse(input string) ([]float64, error) {
-	results := make([]float64, 0)
+// SetMode switches the evaluation mode,
+// the variables keep the values from the previous mode.
+func (p *Parser) SetMode(mode Mode) {
+	p.mode = mode
+}
+
+func (p *Parser) Mode() Mode {
+	return p.mode
+}
+
+// SetPrecision sets the number of significant decimal digits of the exact modes
+func (p *Parser) SetPrecision(digits int) error {
+	if digits < 1 || digits > MaxPrecision {
+		return fmt.Errorf("%w: %d is not in [1, %d]", ErrInvalidPrecision, digits, MaxPrecision)
+	}
+	p.digits = uint(digits)
+
+	return nil
+}
+
+func (p *Parser) Precision() int {
+	return int(p.digits)
+}
+
+// SetJuxtaposition sets the precedence of the implicit multiplication like "2x"
+func (p *Parser) SetJuxtaposition(juxtaposition Juxtaposition) {
+	p.juxtaposition = juxtaposition
+}
+
+func (p *Parser) Juxtaposition() Juxtaposition {
+	return p.juxtaposition
+}
+
+// SetEchoAssignments sets whether the assignments like "x = 5"
+// have their values in the results of Parse.
+func (p *Parser) SetEchoAssignments(echo bool) {
+	p.echo = echo
+}
+
+func (p *Parser) EchoAssignments() bool {
+	return p.echo
+}
+
+// VariableNames returns the sorted names of the variables
+func (p *Parser) VariableNames() []string {
+	names := make([]string, 0, len(p.variables))
+	for name := range p.variables {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// FunctionNames returns the sorted names of the user-defined functions
+func (p *Parser) FunctionNames() []string {
+	names := make([]string, 0, len(p.functions))
+	for name := range p.functions {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// ResultCount returns the number of the results, the last result
+// is "$n" with n of the count, and the previous ones are numbered down to 1.
+func (p *Parser) ResultCount() int {
+	return len(p.results)
+}
+
+// ResetResults clears the results referred by "ans", "_" and "$n"
+func (p *Parser) ResetResults() {
+	p.results = nil
+}
+
+func (p *Parser) scope(results []value.Value) *scope {
+	var s *scope
+	if p.mode != ModeFloat {
+		s = newExactScope(p.variables, p.functions, p.digits)
+	} else {
+		s = newScope(p.variables, p.functions)
+	}
+	s.results = results
+
+	return s
+}
+
+// Parse evaluates the expressions separated by semicolons and returns their results,
+// which are kept for "ans", "_" and "$n" only if all the expressions succeed.
+func (p *Parser) Parse(input string) ([]value.Value, error) {
+	results := make([]value.Value, 0)
+	// The later expressions can refer to the results of the earlier ones
+	all := p.results
 	debug := os.Getenv("DEBUG") != ""
 
 	for stmt := range strings.SplitSeq(input, ";") {
@@ -45,7 +252,7 @@ func (p *Parser) Parse(input string) ([]float64, error) {
 			fmt.Printf("[debug] Tokens: %s\r\n", lexer)
 		}
 
-		expr, err := NewExpressionFromLexer(lexer)
+		expr, err := NewExpressionFromLexerWith(lexer, p.juxtaposition)
 		if err != nil {
 			return nil, fmt.Errorf("error creating expression: %w", err)
 		}
@@ -55,40 +262,50 @@ func (p *Parser) Parse(input string) ([]float64, error) {
 			fmt.Printf("[debug] Expression: %s\r\n", expr)
 		}
 
-		// Handle variable assignment
-		if expr.IsOPAssignment() {
-			varName, rhs := expr.GetAssignment()
-			if varName != "" && rhs != nil {
-				val, err := rhs.Evaluate(p.variables)
-				if err != nil {
-					return nil, fmt.Errorf("error evaluating assignment: %w", err)
-				}
-				p.variables[varName] = val
-
-				// Print dividing line for readability if DEBUG is set
-				if debug {
-					fmt.Printf("------------------------\r\n")
-				}
-
-				continue
+		// Handle function definition
+		if expr.IsFunctionDefinition() {
+			fn, err := expr.getFunctionDefinition()
+			if err != nil {
+				return nil, fmt.Errorf("error defining function: %w", err)
+			}
+			p.functions[fn.name] = fn
+
+			// Show function if DEBUG is set
+			if debug {
+				fmt.Printf("[debug] Function: %s\r\n", fn)
+				fmt.Printf("------------------------\r\n")
+			}
+
+			continue
+		}
+
+		// Handle variable assignment like "x = 5" or "x += 1",
+		// its value is only the result if the assignments are echoed
+		if (expr.IsOPAssignment() || expr.IsOPCompoundAssignment()) && !p.echo {
+			if _, err := expr.evaluateIn(p.scope(all)); err != nil {
+				return nil, fmt.Errorf("error evaluating assignment: %w", err)
+			}
+
+			// Print dividing line for readability if DEBUG is set
+			if debug {
+				fmt.Printf("------------------------\r\n")
 			}
+
+			continue
 		}
 
-		result, err := expr.Evaluate(p.variables)
+		result, err := expr.evaluateIn(p.scope(all))
 		if err != nil {
 			return nil, fmt.Errorf("error evaluating expression: %w", err)
 		}
 
 		// Check if the result is approximately an integer for display
 		// This is to handle cases like 1.99999999999 to 2
-		rounded := math.Round(result)
-		if math.Abs(rounded-result) < IntApproxTolerance {
-			result = rounded
-		}
+		result = roundResult(result)
 
 		// Show result if DEBUG is set
 		if debug {
-			fmt.Printf("[debug] Evaluated: %s\r\n", strconv.FormatFloat(result, 'f', -1, 64))
+			fmt.Printf("[debug] Evaluated: %s\r\n", result)
 		}
 
 		// Print dividing line for readability if DEBUG is set
@@ -97,7 +314,41 @@ func (p *Parser) Parse(input string) ([]float64, error) {
 		}
 
 		results = append(results, result)
+		all = append(all, result)
 	}
+	p.results = all
 
 	return results, nil
 }
+
+// roundResult rounds the float64 numbers in the result
+// if they are approximately integers
+func roundResult(result value.Value) value.Value {
+	switch num := result.(type) {
+	case value.Float:
+		return value.Float(roundApprox(float64(num)))
+	case value.Complex:
+		// Both parts are rounded, so exp(i * pi) is -1 rather than -1+1.2e-16i
+		return value.FromComplex(complex(roundApprox(real(num)), roundApprox(imag(num))))
+	case value.Quantity:
+		return value.NewQuantity(roundApprox(num.Amount()), num.Unit())
+	case value.List:
+		list := make(value.List, 0, len(num))
+		for _, elem := range num {
+			list = append(list, roundResult(elem))
+		}
+		return list
+	default:
+		return result
+	}
+}
+
+// roundApprox rounds the number if it's approximately an integer
+func roundApprox(num float64) float64 {
+	rounded := math.Round(num)
+	if math.Abs(rounded-num) < IntApproxTolerance {
+		return rounded
+	}
+
+	return num
+}
@@ -2,11 +2,15 @@ package parser_test
 
 import (
 	"errors"
+	"math"
 	"slices"
 	"testing"
 
 	"simplecalc/pkg/parser"
+	"simplecalc/pkg/parser/function"
 	"simplecalc/pkg/parser/operator"
+	"simplecalc/pkg/parser/unit"
+	"simplecalc/pkg/parser/value"
 )
 
 func TestParser_Parse_MultiLine(t *testing.T) {
@@ -147,6 +151,101 @@ func TestParser_Parse_MultiLine(t *testing.T) {
 			want:    []float64{-64.0},
 			wantErr: nil,
 		},
+		{
+			name:  "user-defined function",
+			input: "area(r) = 3.14159 * r ** 2; area(2)",
+			want:  []float64{12.56636},
+		},
+		{
+			name:  "user-defined function with multiple parameters",
+			input: "f(x, y) = x * 10 + y; f(1, 2) + f(3, 4)",
+			want:  []float64{46},
+		},
+		{
+			name:  "user-defined function without parameters",
+			input: "g() = 7; g() * 2",
+			want:  []float64{14},
+		},
+		{
+			name:  "parameter shadows global variable",
+			input: "x = 10; f(x) = x * 2; f(3); x",
+			want:  []float64{6, 10},
+		},
+		{
+			name:  "user-defined function refers to global variable",
+			input: "f(x) = x + y; y = 3; f(1)",
+			want:  []float64{4},
+		},
+		{
+			name:  "user-defined function calls another function",
+			input: "sq(x) = x * x; sumsq(a, b) = sq(a) + sq(b); sumsq(3, 4)",
+			want:  []float64{25},
+		},
+		{
+			name:  "redefine user-defined function",
+			input: "f(x) = x; f(x) = x + 1; f(1)",
+			want:  []float64{2},
+		},
+		{
+			name:    "user-defined function with wrong argument count",
+			input:   "f(x, y) = x + y; f(1)",
+			wantErr: function.ErrInvalidArgumentCount,
+		},
+		{
+			name:  "recursion with conditional expression",
+			input: "fact(n) = n <= 1 ? 1 : n * fact(n - 1); fact(10)",
+			want:  []float64{3628800},
+		},
+		{
+			name:  "piecewise function with conditional expressions",
+			input: "clamp(x, lo, hi) = x < lo ? lo : x > hi ? hi : x; clamp(-5, 0, 10); clamp(15, 0, 10); clamp(5, 0, 10)",
+			want:  []float64{0, 10, 5},
+		},
+		{
+			name:    "unbounded recursion",
+			input:   "f(x) = 1 + f(x - 1); f(3)",
+			wantErr: parser.ErrMaxCallDepth,
+		},
+		{
+			name:    "redefine built-in function",
+			input:   "sqrt(x) = x",
+			wantErr: parser.ErrRedefineBuiltin,
+		},
+		{
+			name:    "duplicate parameters",
+			input:   "f(x, x) = x",
+			wantErr: parser.ErrDuplicateParameter,
+		},
+		{
+			name:    "number as parameter",
+			input:   "f(1) = 2",
+			wantErr: parser.ErrInvalidParameter,
+		},
+		{
+			name:    "assign to an operation",
+			input:   "(a + b) = 3",
+			wantErr: parser.ErrInvalidAssignment,
+		},
+		{
+			name:  "constants without assignment",
+			input: "tau / pi; round(e * 1000); phi ** 2 - phi",
+			want:  []float64{2, 2718, 1},
+		},
+		{
+			name:    "assign to constant",
+			input:   "pi = 3",
+			wantErr: parser.ErrAssignConstant,
+		},
+		{
+			name:    "constant as function parameter",
+			input:   "f(e) = e * 2",
+			wantErr: parser.ErrAssignConstant,
+		},
+		{
+			name:  "infinity constant",
+			input: "min(inf, 5); -inf",
+			want:  []float64{5, math.Inf(-1)},
+		},
 	}
 
 	for _, tt := range tests {
@@ -159,9 +258,729 @@ func TestParser_Parse_MultiLine(t *testing.T) {
 				return
 			}
 
-			if !slices.Equal(got, tt.want) {
+			nums := make([]float64, 0, len(got))
+			for _, val := range got {
+				num, err := value.ToFloat(val)
+				if err != nil {
+					t.Fatalf("Parse() got = %v, want numbers", got)
+				}
+				nums = append(nums, num)
+			}
+			if !slices.Equal(nums, tt.want) {
 				t.Errorf("Parse() got = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestParser_Parse_Values(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr error
+	}{
+		{
+			name:  "comparison operators",
+			input: "1 < 2; 2 <= 1; 3 > 3; 3 >= 3; 1 == 1; 1 != 1",
+			want:  []string{"true", "false", "false", "true", "true", "false"},
+		},
+		{
+			name:  "logical operators",
+			input: "x = 4; y = 2; x > 3 && y <= 2; x < 3 || y < 2; !(x > 3)",
+			want:  []string{"true", "false", "false"},
+		},
+		{
+			name:  "numbers are truthy unless zero",
+			input: "5 && 2; 0 || 0; !0; !-1",
+			want:  []string{"true", "false", "true", "false"},
+		},
+		{
+			name:  "comparison binds looser than arithmetic",
+			input: "1 + 2 == 3; 2 * 3 > 5 == 1",
+			want:  []string{"true", "true"},
+		},
+		{
+			name:  "and binds tighter than or",
+			input: "1 == 1 || 1 == 2 && 1 == 2",
+			want:  []string{"true"},
+		},
+		{
+			name:  "booleans are 1 and 0 in arithmetic",
+			input: "(1 < 2) + (1 < 2) + (2 < 1)",
+			want:  []string{"2"},
+		},
+		{
+			name:  "boolean variable",
+			input: "b = 3 > 2; b; b && 0",
+			want:  []string{"true", "false"},
+		},
+		{
+			name:  "and is short-circuited",
+			input: "x = 0; x != 0 && 1 / x > 2",
+			want:  []string{"false"},
+		},
+		{
+			name:  "or is short-circuited",
+			input: "x = 0; x == 0 || 1 / x > 2",
+			want:  []string{"true"},
+		},
+		{
+			name:  "complex multiplication",
+			input: "(3 + 4i) * (1 - 2i)",
+			want:  []string{"11-2i"},
+		},
+		{
+			name:  "square root of negative number",
+			input: "sqrt(-1); sqrt(-4) + 1",
+			want:  []string{"1i", "1+2i"},
+		},
+		{
+			name:  "complex division and power",
+			input: "1 / (2i); (1 + 1i) ** 2; 2j ** -2",
+			want:  []string{"-0.5i", "2i", "-0.25"},
+		},
+		{
+			name:  "real result is not complex",
+			input: "z = 1 + 2i; z * conj(z); (1 + 1i) * (1 - 1i) > 1",
+			want:  []string{"5", "true"},
+		},
+		{
+			name:  "euler's identity",
+			input: "exp(1i * pi) + 1",
+			want:  []string{"0"},
+		},
+		{
+			name:  "negative base with fractional exponent",
+			input: "(-8) ** (1 / 3)",
+			want:  []string{"1+1.732050807568877i"},
+		},
+		{
+			name:  "complex equality",
+			input: "3 + 4i == 3 + 4i; 1i != 1j",
+			want:  []string{"true", "false"},
+		},
+		{
+			name:    "complex numbers are not ordered",
+			input:   "1i < 2",
+			wantErr: operator.ErrInvalidOperand,
+		},
+		{
+			name:  "speed from distance and time",
+			input: "5 km / 20 min; 5 km / 20 min in km/h",
+			want:  []string{"0.25 km/min", "15 km/h"},
+		},
+		{
+			name:  "conversion with to",
+			input: "1 mi to km; 1 L to mL",
+			want:  []string{"1.609344 km", "1000 mL"},
+		},
+		{
+			name:  "temperature conversion",
+			input: "100 degC in degF; -40 degF in degC; 0 degC in K",
+			want:  []string{"212 degF", "-40 degC", "273.15 K"},
+		},
+		{
+			name:  "add compatible units",
+			input: "5 m + 20 cm; 1 h - 30 min; -5 m",
+			want:  []string{"5.2 m", "0.5 h", "-5 m"},
+		},
+		{
+			name:  "combine dimensions",
+			input: "3 m * 4 m; (3 m) ** 2 in cm**2; 10 N * 3 m in J; 1 / (2 s) in Hz",
+			want:  []string{"12 m**2", "90000 cm**2", "30 J", "0.5 Hz"},
+		},
+		{
+			name:  "dimensionless result is a number",
+			input: "1 km / 1 m; 2 h / 30 min",
+			want:  []string{"1000", "4"},
+		},
+		{
+			name:  "compare compatible units",
+			input: "5 m > 400 cm; 1 kg == 1000 g",
+			want:  []string{"true", "true"},
+		},
+		{
+			name:  "unit names are shadowed by variables",
+			input: "m = 5; 2m; 2m + 1; 2 * m; 2 h in min; 1 km in m",
+			want:  []string{"10", "11", "10", "120 min", "1000 m"},
+		},
+		{
+			name:  "unit names are shadowed by function parameters",
+			input: "f(s) = 2s + 1; f(3); 2s",
+			want:  []string{"7", "2 s"},
+		},
+		{
+			name:  "units are variables",
+			input: "g0 = 9.81 m/s**2; g0 * 2 s",
+			want:  []string{"19.62 m/s"},
+		},
+		{
+			name:    "add incompatible units",
+			input:   "5 m + 2 s",
+			wantErr: unit.ErrIncompatibleUnits,
+		},
+		{
+			name:    "convert to incompatible unit",
+			input:   "5 m in s",
+			wantErr: unit.ErrIncompatibleUnits,
+		},
+		{
+			name:    "convert to number",
+			input:   "5 m in 2",
+			wantErr: operator.ErrInvalidOperand,
+		},
+		{
+			name:    "convert to unit with number",
+			input:   "5 m in 2 km",
+			wantErr: operator.ErrInvalidOperand,
+		},
+		{
+			name:  "convert to compound unit",
+			input: "36 km/h to m/s; 1 km * h / h in m",
+			want:  []string{"10 m/s", "1000 m"},
+		},
+		{
+			name:    "fractional power of unit",
+			input:   "(4 m) ** 0.5",
+			wantErr: operator.ErrInvalidOperand,
+		},
+		{
+			name:  "compound assignments",
+			input: "total = 1; total += 5; total -= 2; total *= 2 + 1; total /= 4; total **= 2; total",
+			want:  []string{"9"},
+		},
+		{
+			name:  "compound assignment of list and quantity",
+			input: "v = [1, 2]; v *= 3; d = 3 km; d += 200 m; v; d",
+			want:  []string{"[3, 6]", "3.2 km"},
+		},
+		{
+			name:    "compound assignment to undefined variable",
+			input:   "x = 1; y += x",
+			wantErr: parser.ErrUndefinedVariable,
+		},
+		{
+			name:    "compound assignment to unit",
+			input:   "km += 1",
+			wantErr: parser.ErrUndefinedVariable,
+		},
+		{
+			name:    "nested compound assignment to unit",
+			input:   "2 * (km += 1)",
+			wantErr: parser.ErrUndefinedVariable,
+		},
+		{
+			name:    "nested compound assignment to result",
+			input:   "5; 0 + (ans += 1)",
+			wantErr: parser.ErrInvalidAssignment,
+		},
+		{
+			name:    "compound assignment to constant",
+			input:   "pi *= 2",
+			wantErr: parser.ErrAssignConstant,
+		},
+		{
+			name:    "compound assignment to number",
+			input:   "2 += 1",
+			wantErr: parser.ErrInvalidAssignment,
+		},
+		{
+			name:  "chained and nested assignments",
+			input: "a = b = 3; a + b; y = (x = 2) * 3; x; y; n = 0; m = n += 5; m",
+			want:  []string{"6", "2", "6", "5"},
+		},
+		{
+			name:  "assignment in function body changes argument",
+			input: "f(x) = (x = x + 1) * x; f(2); x = 7; f(1); x",
+			want:  []string{"9", "4", "7"},
+		},
+		{
+			name:  "list variable and indexing",
+			input: "v = [1, 2, 3]; v; v[0]; v[-1]; [[1, 2], [3, 4]][1][0]",
+			want:  []string{"[1, 2, 3]", "1", "3", "3"},
+		},
+		{
+			name:  "element-wise arithmetic",
+			input: "v = [1, 2, 3]; v + [10, 20, 30]; v * 2; 2 ** v; -v; v % 2",
+			want:  []string{"[11, 22, 33]", "[2, 4, 6]", "[2, 4, 8]", "[-1, -2, -3]", "[1, 0, 1]"},
+		},
+		{
+			name:  "aggregate functions",
+			input: "v = [1, 2, 3, 4]; sum(v); mean(v); len(v); dot(v, [1, 0, 1, 0]); sum(1, 2, 3)",
+			want:  []string{"10", "2.5", "4", "4", "6"},
+		},
+		{
+			name:  "aggregate functions of empty list",
+			input: "sum([]); len([])",
+			want:  []string{"0", "0"},
+		},
+		{
+			name:  "user-defined function with list",
+			input: "scale(v, k) = v * k; scale([1, 2], 3)",
+			want:  []string{"[3, 6]"},
+		},
+		{
+			name:    "element-wise arithmetic with different lengths",
+			input:   "[1, 2] + [1, 2, 3]",
+			wantErr: value.ErrLengthMismatch,
+		},
+		{
+			name:    "index out of range",
+			input:   "v = [1, 2]; v[2]",
+			wantErr: parser.ErrIndexOutOfRange,
+		},
+		{
+			name:    "index is not an integer",
+			input:   "v = [1, 2]; v[0.5]",
+			wantErr: parser.ErrInvalidIndex,
+		},
+		{
+			name:  "matrix multiplication",
+			input: "A = [[1, 2], [3, 4]]; A @ A; A * A; A @ [1, 1]; [1, 1] @ A; [1, 2] @ [3, 4]",
+			want:  []string{"[[7, 10], [15, 22]]", "[[1, 4], [9, 16]]", "[3, 7]", "[4, 6]", "11"},
+		},
+		{
+			name:  "matrix functions",
+			input: "A = [[2, 1], [1, 3]]; transpose([[1, 2, 3]]); det(A); inverse([[2, 0], [0, 4]]); solve(A, [3, 5]); det([[1, 2], [2, 4]])",
+			want:  []string{"[[1], [2], [3]]", "5", "[[0.5, 0], [0, 0.25]]", "[0.8, 1.4]", "0"},
+		},
+		{
+			name:    "matrix multiplication with mismatched shapes",
+			input:   "[[1, 2, 3]] @ [[1, 2]]",
+			wantErr: value.ErrShapeMismatch,
+		},
+		{
+			name:    "determinant of non-square matrix",
+			input:   "det([[1, 2, 3], [4, 5, 6]])",
+			wantErr: value.ErrShapeMismatch,
+		},
+		{
+			name:    "determinant of empty matrix",
+			input:   "det([[]])",
+			wantErr: value.ErrShapeMismatch,
+		},
+		{
+			name:    "matrix with empty row",
+			input:   "[[1, 2], []] @ [1, 1]",
+			wantErr: value.ErrShapeMismatch,
+		},
+		{
+			name:    "solve with mismatched vector",
+			input:   "solve([[1, 2], [3, 4]], [1, 2, 3])",
+			wantErr: value.ErrShapeMismatch,
+		},
+		{
+			name:    "inverse of singular matrix",
+			input:   "inverse([[0.1, 0.2], [0.3, 0.6]])",
+			wantErr: function.ErrSingularMatrix,
+		},
+		{
+			name:    "matrix with rows of different lengths",
+			input:   "[[1, 2], [3]] @ [1, 2]",
+			wantErr: value.ErrInvalidType,
+		},
+		{
+			name:    "mean of empty list",
+			input:   "mean([])",
+			wantErr: function.ErrOutOfDomain,
+		},
+		{
+			name:    "right-hand side is evaluated if not short-circuited",
+			input:   "x = 0; x == 0 && 1 / x > 2",
+			wantErr: operator.ErrDivisionByZero,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := parser.NewParser()
+			got, err := p.Parse(tt.input)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Parse() error = %q, wantErr %q", err, tt.wantErr)
+				return
+			}
+
+			strs := make([]string, 0, len(got))
+			for _, val := range got {
+				strs = append(strs, val.String())
+			}
+			if !slices.Equal(strs, tt.want) {
+				t.Errorf("Parse() got = %v, want %v", strs, tt.want)
+			}
+		})
+	}
+}
+
+func TestParser_Parse_ExactMode(t *testing.T) {
+	tests := []struct {
+		name      string
+		precision int
+		input     string
+		want      []string
+		wantErr   error
+	}{
+		{
+			name:  "decimal fractions are exact",
+			input: "0.1 + 0.2; 0.1 + 0.2 == 0.3",
+			want:  []string{"0.3", "true"},
+		},
+		{
+			name:  "large integer beyond float64",
+			input: "2 ** 100",
+			want:  []string{"1267650600228229401496703205376"},
+		},
+		{
+			name:  "hexadecimal literal beyond int64",
+			input: "0x10000000000000000000; 0x1_0000_0000_0000_0000 - 1",
+			want:  []string{"75557863725914323419136", "18446744073709551615"},
+		},
+		{
+			name:  "bitwise operators on large integers",
+			input: "1 << 63; (2 ** 53 + 1) & 1; ~(2 ** 64); 0xFFFF_FFFF_FFFF_FFFF_FF >> 4 ^ 1",
+			want:  []string{"9223372036854775808", "1", "-18446744073709551617", "295147905179352825854"},
+		},
+		{
+			name:    "bitwise operator on fraction",
+			input:   "(1 / 2) | 1",
+			wantErr: operator.ErrInvalidOperand,
+		},
+		{
+			name:  "large literal is not out of range",
+			input: "6.02e23 * 10",
+			want:  []string{"6020000000000000000000000"},
+		},
+		{
+			name:      "repeating decimal with precision",
+			precision: 20,
+			input:     "1 / 3; 2 / 3",
+			want:      []string{"0.33333333333333333333", "0.66666666666666666667"},
+		},
+		{
+			name:  "rational stays exact through variables",
+			input: "x = 1 / 3; x * 3; -x * 3",
+			want:  []string{"1", "-1"},
+		},
+		{
+			name:      "square root",
+			precision: 30,
+			input:     "sqrt(2); sqrt(2) ** 2; sqrt(9 / 4)",
+			want:      []string{"1.41421356237309504880168872421", "2", "1.5"},
+		},
+		{
+			name:      "constant with precision",
+			precision: 30,
+			input:     "pi",
+			want:      []string{"3.14159265358979323846264338328"},
+		},
+		{
+			name:  "floor division and modulo",
+			input: "7.5 // 2; -7 % 3; -7 rem 3",
+			want:  []string{"3", "2", "-1"},
+		},
+		{
+			name:  "integer exponent beyond the exact limit",
+			input: "2 ** 10001; (-2) ** 10001",
+			want: []string{
+				"3.9901262337615167697674843253671701676469936637724e+3010",
+				"-3.9901262337615167697674843253671701676469936637724e+3010",
+			},
+		},
+		{
+			name:    "power overflows",
+			input:   "2 ** 1e10",
+			wantErr: operator.ErrInvalidOperand,
+		},
+		{
+			name:    "power underflows",
+			input:   "0.5 ** 100000000",
+			wantErr: operator.ErrInvalidOperand,
+		},
+		{
+			name:    "power with fractional exponent overflows",
+			input:   "2 ** 10000.5",
+			wantErr: operator.ErrInvalidOperand,
+		},
+		{
+			name:  "negative integer exponent",
+			input: "2 ** -3",
+			want:  []string{"0.125"},
+		},
+		{
+			name:  "unit name shadowed by variable",
+			input: "m = 1 / 3; 3m",
+			want:  []string{"1"},
+		},
+		{
+			name:  "user-defined function",
+			input: "f(x) = x * x + 1; f(1 / 2)",
+			want:  []string{"1.25"},
+		},
+		{
+			name:    "division by zero",
+			input:   "1 / (0.5 - 1 / 2)",
+			wantErr: operator.ErrDivisionByZero,
+		},
+		{
+			name:    "zero to negative power",
+			input:   "0 ** -1",
+			wantErr: operator.ErrDivisionByZero,
+		},
+		{
+			name:  "square root of negative number",
+			input: "sqrt(-1 / 4)",
+			want:  []string{"0.5i"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := parser.NewParser()
+			p.SetMode(parser.ModeExact)
+			if tt.precision != 0 {
+				if err := p.SetPrecision(tt.precision); err != nil {
+					t.Fatalf("SetPrecision() error = %v", err)
+				}
+			}
+			got, err := p.Parse(tt.input)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Parse() error = %q, wantErr %q", err, tt.wantErr)
+				return
+			}
+
+			strs := make([]string, 0, len(got))
+			for _, val := range got {
+				strs = append(strs, val.String())
+			}
+			if !slices.Equal(strs, tt.want) {
+				t.Errorf("Parse() got = %v, want %v", strs, tt.want)
+			}
+		})
+	}
+}
+
+func TestParser_SetPrecision(t *testing.T) {
+	p := parser.NewParser()
+	for _, digits := range []int{0, -1, parser.MaxPrecision + 1} {
+		if err := p.SetPrecision(digits); !errors.Is(err, parser.ErrInvalidPrecision) {
+			t.Errorf("SetPrecision(%d) error = %v, want %v", digits, err, parser.ErrInvalidPrecision)
+		}
+	}
+	if p.Precision() != value.DefaultDigits {
+		t.Errorf("Precision() = %d, want %d", p.Precision(), value.DefaultDigits)
+	}
+}
+
+func TestParser_Parse_RationalMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr error
+	}{
+		{
+			name:  "sum of fractions is reduced",
+			input: "1 / 3 + 1 / 6",
+			want:  []string{"1/2"},
+		},
+		{
+			name:  "recipe scaling",
+			input: "cups = 3 / 4; cups * 2 / 3; cups * 5",
+			want:  []string{"1/2", "15/4"},
+		},
+		{
+			name:  "integer exponent stays exact",
+			input: "(2 / 3) ** 3; (2 / 3) ** -2; 10 ** 20 / 3",
+			want:  []string{"8/27", "9/4", "100000000000000000000/3"},
+		},
+		{
+			name:  "decimal literals are fractions",
+			input: "0.25 + 0.5; 1.5e-3",
+			want:  []string{"3/4", "3/2000"},
+		},
+		{
+			name:  "integer result",
+			input: "1 / 2 + 1 / 2",
+			want:  []string{"1"},
+		},
+		{
+			name:  "irrational result is decimal",
+			input: "sqrt(2) / 2",
+			want:  []string{"0.70710678118654752440084436210484903928483593768847"},
+		},
+		{
+			name:  "matrix inverse is exact",
+			input: "A = [[1, 2], [3, 4]]; inverse(A); A @ inverse(A); det([[1 / 2, 1 / 3], [1 / 4, 1 / 5]])",
+			want:  []string{"[[-2, 1], [3/2, -1/2]]", "[[1, 0], [0, 1]]", "1/60"},
+		},
+		{
+			name:  "statistics stay exact",
+			input: "mean(1, 2, 4); median(1 / 2, 1 / 3); variance(1, 2, 3, 4); mode(1 / 3, 2 / 6, 1 / 2)",
+			want:  []string{"7/3", "5/12", "5/3", "1/3"},
+		},
+		{
+			name:  "factorial and percent stay exact",
+			input: "30!; 25! / 24!; 1 / 3 * 15%",
+			want:  []string{"265252859812191058636308480000000", "25", "1/20"},
+		},
+		{
+			name:    "division by zero",
+			input:   "1 / (1 / 2 - 2 / 4)",
+			wantErr: operator.ErrDivisionByZero,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := parser.NewParser()
+			p.SetMode(parser.ModeRational)
+			got, err := p.Parse(tt.input)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Parse() error = %q, wantErr %q", err, tt.wantErr)
+				return
+			}
+
+			strs := make([]string, 0, len(got))
+			for _, val := range got {
+				strs = append(strs, value.FormatValue(val, p.Mode().Format()))
+			}
+			if !slices.Equal(strs, tt.want) {
+				t.Errorf("Parse() got = %v, want %v", strs, tt.want)
+			}
+		})
+	}
+}
+
+func TestParser_Parse_ShapeError(t *testing.T) {
+	p := parser.NewParser()
+	_, err := p.Parse("[[1, 2], [3, 4]] @ [[1, 2, 3]]")
+
+	var shapeErr *value.ShapeError
+	if !errors.As(err, &shapeErr) {
+		t.Fatalf("Parse() error = %v, want %T", err, shapeErr)
+	}
+	want := []value.Shape{{Rows: 2, Cols: 2}, {Rows: 1, Cols: 3}}
+	if shapeErr.Op != "@" || !slices.Equal(shapeErr.Shapes, want) {
+		t.Errorf("Parse() error = %+v, want shapes %v of '@'", shapeErr, want)
+	}
+}
+
+func TestParser_SetEchoAssignments(t *testing.T) {
+	p := parser.NewParser()
+	if p.EchoAssignments() {
+		t.Fatalf("EchoAssignments() = true, want false by default")
+	}
+
+	p.SetEchoAssignments(true)
+	got, err := p.Parse("x = 5; a = b = x - 2; a += 1; f(y) = y; f(a) + b")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	want := []value.Value{value.Float(5), value.Float(3), value.Float(4), value.Float(7)}
+	if !slices.Equal(got, want) {
+		t.Errorf("Parse() = %v, want %v", got, want)
+	}
+}
+
+func TestParser_SetJuxtaposition(t *testing.T) {
+	p := parser.NewParser()
+	if p.Juxtaposition() != parser.JuxtapositionEqual {
+		t.Fatalf("Juxtaposition() = %v, want %v", p.Juxtaposition(), parser.JuxtapositionEqual)
+	}
+
+	p.SetJuxtaposition(parser.JuxtapositionTight)
+	got, err := p.Parse("x = 4; 8 / 2x; 2(x + 1)")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	want := []value.Value{value.Float(1), value.Float(10)}
+	if !slices.Equal(got, want) {
+		t.Errorf("Parse() = %v, want %v", got, want)
+	}
+
+	for _, j := range []parser.Juxtaposition{parser.JuxtapositionEqual, parser.JuxtapositionTight} {
+		got, err := parser.ParseJuxtaposition(j.String())
+		if err != nil || got != j {
+			t.Errorf("ParseJuxtaposition(%q) = %v, %v, want %v", j.String(), got, err, j)
+		}
+	}
+	if _, err := parser.ParseJuxtaposition("loose"); !errors.Is(err, parser.ErrInvalidJuxtaposition) {
+		t.Errorf("ParseJuxtaposition() error = %v, want %v", err, parser.ErrInvalidJuxtaposition)
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	for _, m := range []parser.Mode{parser.ModeFloat, parser.ModeExact, parser.ModeRational} {
+		got, err := parser.ParseMode(m.String())
+		if err != nil || got != m {
+			t.Errorf("ParseMode(%q) = %v, %v, want %v", m.String(), got, err, m)
+		}
+	}
+
+	if _, err := parser.ParseMode("complex"); !errors.Is(err, parser.ErrInvalidMode) {
+		t.Errorf("ParseMode() error = %v, want %v", err, parser.ErrInvalidMode)
+	}
+}
+
+func TestParser_Names(t *testing.T) {
+	p := parser.NewParser()
+	if _, err := p.Parse("y = 2; x = 1; area(r) = pi * r ** 2; f(x) = x"); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if got, want := p.VariableNames(), []string{"x", "y"}; !slices.Equal(got, want) {
+		t.Errorf("VariableNames() = %v, want %v", got, want)
+	}
+	if got, want := p.FunctionNames(), []string{"area", "f"}; !slices.Equal(got, want) {
+		t.Errorf("FunctionNames() = %v, want %v", got, want)
+	}
+}
+
+func TestParser_Results(t *testing.T) {
+	p := parser.NewParser()
+	steps := []struct {
+		input     string
+		want      []value.Value
+		wantErr   error
+		wantCount int
+	}{
+		{input: "ans", wantErr: parser.ErrNoResult},
+		{input: "6 * 7; x = 2", want: []value.Value{value.Float(42)}, wantCount: 1},
+		{input: "ans + 1; _ * x", want: []value.Value{value.Float(43), value.Float(86)}, wantCount: 3},
+		{input: "$1 + $2; f(n) = n + $1; f(_)", want: []value.Value{value.Float(85), value.Float(127)}, wantCount: 5},
+		{input: "$4", want: []value.Value{value.Float(85)}, wantCount: 6},
+		// The results of the failed input are not kept
+		{input: "1; 1 / 0", wantErr: operator.ErrDivisionByZero, wantCount: 6},
+		{input: "$7", wantErr: parser.ErrNoResult, wantCount: 6},
+		{input: "$0", wantErr: parser.ErrNoResult, wantCount: 6},
+		{input: "$1 = 2", wantErr: parser.ErrInvalidAssignment, wantCount: 6},
+		{input: "g($1) = 2", wantErr: parser.ErrInvalidParameter, wantCount: 6},
+		// The results can't be hidden by the variables
+		{input: "ans = 5", wantErr: parser.ErrInvalidAssignment, wantCount: 6},
+		{input: "7; 0 + (_ = 1)", wantErr: parser.ErrInvalidAssignment, wantCount: 6},
+		{input: "ans", want: []value.Value{value.Float(85)}, wantCount: 7},
+	}
+	for _, step := range steps {
+		got, err := p.Parse(step.input)
+		if !errors.Is(err, step.wantErr) {
+			t.Fatalf("Parse(%q) error = %v, wantErr %v", step.input, err, step.wantErr)
+		}
+		if err == nil && !slices.Equal(got, step.want) {
+			t.Errorf("Parse(%q) = %v, want %v", step.input, got, step.want)
+		}
+		if p.ResultCount() != step.wantCount {
+			t.Errorf("ResultCount() after %q = %d, want %d", step.input, p.ResultCount(), step.wantCount)
+		}
+	}
+
+	p.ResetResults()
+	if p.ResultCount() != 0 {
+		t.Errorf("ResultCount() after ResetResults() = %d, want 0", p.ResultCount())
+	}
+	if _, err := p.Parse("$1"); !errors.Is(err, parser.ErrNoResult) {
+		t.Errorf("Parse(%q) after ResetResults() error = %v, want %v", "$1", err, parser.ErrNoResult)
+	}
+}
@@ -0,0 +1,186 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"simplecalc/pkg/parser/unit"
+	"simplecalc/pkg/parser/value"
+)
+
+// MaxCallDepth limits the nesting of user-defined function calls
+// to stop unbounded recursion like "f(x) = f(x)"
+const MaxCallDepth = 256
+
+var (
+	ErrMaxCallDepth       = fmt.Errorf("maximum call depth exceeded")
+	ErrInvalidParameter   = fmt.Errorf("invalid function parameter")
+	ErrDuplicateParameter = fmt.Errorf("duplicate function parameter")
+	ErrRedefineBuiltin    = fmt.Errorf("cannot redefine built-in function")
+	ErrNoResult           = fmt.Errorf("no such result")
+)
+
+// userFunction is a function defined from the input like "f(x, y) = x * y"
+type userFunction struct {
+	name   string
+	params []string
+	body   *Expression
+}
+
+func (f *userFunction) String() string {
+	return fmt.Sprintf("%s(%s) = %s", f.name, strings.Join(f.params, ", "), f.body)
+}
+
+// scope holds everything an expression can refer to while being evaluated
+type scope struct {
+	variables map[string]value.Value
+	functions map[string]*userFunction
+
+	// locals holds the arguments of the user-defined function being called,
+	// they shadow the global variables with the same names.
+	locals map[string]value.Value
+	depth  int
+
+	// results are the previous results referred by "ans", "_" and "$n"
+	results []value.Value
+
+	// exact evaluates the numbers as value.Rational with
+	// the number of significant decimal digits for display
+	exact  bool
+	digits uint
+}
+
+func newScope(variables map[string]value.Value, functions map[string]*userFunction) *scope {
+	return &scope{
+		variables: variables,
+		functions: functions,
+	}
+}
+
+// newExactScope creates a scope evaluating the numbers exactly
+func newExactScope(variables map[string]value.Value, functions map[string]*userFunction, digits uint) *scope {
+	return &scope{
+		variables: variables,
+		functions: functions,
+		exact:     true,
+		digits:    digits,
+	}
+}
+
+// lookup returns the value of the constant, the variable
+// from the locals or the globals, or the unit
+func (s *scope) lookup(varName string) (value.Value, bool) {
+	if s.exact {
+		if val, ok := getExactConstant(varName, s.digits); ok {
+			return val, true
+		}
+	} else if val, ok := GetConstant(varName); ok {
+		return value.Float(val), true
+	}
+
+	if val, ok := s.locals[varName]; ok {
+		return val, true
+	}
+
+	if val, ok := s.variables[varName]; ok {
+		return val, true
+	}
+
+	if val, ok := s.result(varName); ok {
+		return val, true
+	}
+
+	// The names of units like km are the last resort, so
+	// the variables with the same names shadow them
+	if u, err := unit.Lookup(varName); err == nil {
+		return value.NewQuantity(1, u), true
+	}
+
+	return nil, false
+}
+
+// isVariable checks if the variable is assigned in the locals or the globals
+func (s *scope) isVariable(varName string) bool {
+	if _, ok := s.locals[varName]; ok {
+		return true
+	}
+
+	_, ok := s.variables[varName]
+	return ok
+}
+
+// result returns the last result for "ans" and "_",
+// or the result numbered n from 1 for "$n"
+func (s *scope) result(name string) (value.Value, bool) {
+	if name == "ans" || name == "_" {
+		if len(s.results) == 0 {
+			return nil, false
+		}
+		return s.results[len(s.results)-1], true
+	}
+
+	n, ok := resultNumber(name)
+	if !ok || n < 1 || n > len(s.results) {
+		return nil, false
+	}
+	return s.results[n-1], true
+}
+
+// resultNumber returns n of the result reference like "$n"
+func resultNumber(name string) (int, bool) {
+	digits, ok := strings.CutPrefix(name, "$")
+	if !ok {
+		return 0, false
+	}
+
+	n, err := strconv.Atoi(digits)
+	return n, err == nil
+}
+
+// isResultReference checks if the name refers to the previous results
+func isResultReference(name string) bool {
+	_, ok := resultNumber(name)
+	return ok || name == "ans" || name == "_"
+}
+
+// assign sets the argument of the user-defined function being called,
+// or the global variable with the name.
+func (s *scope) assign(varName string, val value.Value) {
+	if _, ok := s.locals[varName]; ok {
+		s.locals[varName] = val
+		return
+	}
+
+	if s.variables == nil {
+		s.variables = make(map[string]value.Value)
+	}
+	s.variables[varName] = val
+}
+
+// call creates the scope to evaluate the body of a user-defined function,
+// only the arguments and the global variables are visible from the body.
+func (s *scope) call(fn *userFunction, args []value.Value) (*scope, error) {
+	if s.depth >= MaxCallDepth {
+		return nil, maxCallDepthError(fn)
+	}
+
+	locals := make(map[string]value.Value, len(fn.params))
+	for i, param := range fn.params {
+		locals[param] = args[i]
+	}
+
+	return &scope{
+		variables: s.variables,
+		functions: s.functions,
+		locals:    locals,
+		depth:     s.depth + 1,
+		results:   s.results,
+		exact:     s.exact,
+		digits:    s.digits,
+	}, nil
+}
+
+func maxCallDepthError(fn *userFunction) error {
+	return fmt.Errorf("%w: %d nested calls of '%s'", ErrMaxCallDepth, MaxCallDepth, fn.name)
+}
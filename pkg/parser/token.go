@@ -2,7 +2,9 @@ package parser
 
 import (
 	"fmt"
+	"math/big"
 	"strconv"
+	"strings"
 
 	"simplecalc/pkg/parser/operator"
 )
@@ -135,12 +137,46 @@ func (t Token) GetVarName() string {
 	return t.literal
 }
 
+// IsInteger checks if the token is an integer literal like 42 or 0xFF,
+// not a number with a decimal point or an exponent like 6.02e23
+func (t Token) IsInteger() bool {
+	if t.typ != TokenAtom || t.isVariable {
+		return false
+	}
+
+	if len(t.literal) > 2 && t.literal[0] == '0' && strings.ContainsRune("xXbBoO", rune(t.literal[1])) {
+		return true
+	}
+	return !strings.ContainsAny(t.literal, ".eEij")
+}
+
+// IsImaginary checks if the token is an imaginary number like 4i or 2.5j
+func (t Token) IsImaginary() bool {
+	return t.typ == TokenAtom && !t.isVariable && strings.ContainsAny(t.literal[len(t.literal)-1:], "ij")
+}
+
+// GetValue returns the value of the number token,
+// without the suffix for imaginary numbers.
 func (t Token) GetValue() float64 {
 	if t.typ != TokenAtom || t.isVariable {
 		return 0
 	}
 
-	value, err := strconv.ParseFloat(t.literal, 64)
+	// Integer literals with a base prefix like 0xFF, 0b1010 and 0o755
+	if len(t.literal) > 2 && t.literal[0] == '0' && strings.ContainsRune("xXbBoO", rune(t.literal[1])) {
+		value, ok := new(big.Int).SetString(t.literal, 0)
+		if !ok {
+			// Must be a bug from the lexer, don't recover it
+			panic(fmt.Sprintf("failed to parse token value: '%s'", t.literal))
+		}
+
+		// The integers beyond float64 are infinity
+		f, _ := new(big.Float).SetInt(value).Float64()
+		return f
+	}
+
+	// Remove digit separators like 1_000_000 and the imaginary suffix
+	value, err := strconv.ParseFloat(strings.TrimRight(strings.ReplaceAll(t.literal, "_", ""), "ij"), 64)
 	if err != nil {
 		// Must be a bug, don't recover it
 		// This should not happen as we are already checking the type
@@ -151,6 +187,33 @@ func (t Token) GetValue() float64 {
 	return value
 }
 
+// GetExactValue returns the exact value of the number token
+func (t Token) GetExactValue() *big.Rat {
+	if t.typ != TokenAtom || t.isVariable {
+		return new(big.Rat)
+	}
+
+	// Integer literals with a base prefix like 0xFF, 0b1010 and 0o755
+	if len(t.literal) > 2 && t.literal[0] == '0' && strings.ContainsRune("xXbBoO", rune(t.literal[1])) {
+		value, ok := new(big.Int).SetString(t.literal, 0)
+		if !ok {
+			// Must be a bug from the lexer, don't recover it
+			panic(fmt.Sprintf("failed to parse token value: '%s'", t.literal))
+		}
+
+		return new(big.Rat).SetInt(value)
+	}
+
+	// Remove digit separators like 1_000_000 and the imaginary suffix
+	value, ok := new(big.Rat).SetString(strings.TrimRight(strings.ReplaceAll(t.literal, "_", ""), "ij"))
+	if !ok {
+		// Must be a bug from the lexer, don't recover it
+		panic(fmt.Sprintf("failed to parse token value: '%s'", t.literal))
+	}
+
+	return value
+}
+
 func (t Token) GetType() TokenType {
 	return t.typ
 }
@@ -179,6 +242,14 @@ func (t Token) IsPrefixOperator() bool {
 	return t.operator.IsPrefixOperator()
 }
 
+func (t Token) IsPostfixOperator() bool {
+	if t.typ != TokenOperator {
+		panic(fmt.Sprintf("'%s' (%s) is not a operator", t.literal, t.typ))
+	}
+
+	return t.operator.IsPostfixOperator()
+}
+
 // GetInfixBindingPower returns the left and right binding powers of an infix operator.
 // Two different binding powers determine the ordering behavior to ensure predictable
 // and testable parsing.
@@ -199,3 +270,13 @@ func (t Token) GetPrefixBindingPower() (float32, error) {
 
 	return t.operator.GetPrefixBindingPower()
 }
+
+// GetPostfixBindingPower returns the left-hand side binding power
+// of the postfix operator, it has no right-hand side.
+func (t Token) GetPostfixBindingPower() (float32, error) {
+	if t.typ != TokenOperator {
+		return 0, fmt.Errorf("'%s' is not a operator", t.literal)
+	}
+
+	return t.operator.GetPostfixBindingPower()
+}
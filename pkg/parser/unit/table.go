@@ -0,0 +1,123 @@
+package unit
+
+import "fmt"
+
+var (
+	length      = Dimension{1, 0, 0, 0, 0, 0, 0}
+	mass        = Dimension{0, 1, 0, 0, 0, 0, 0}
+	time        = Dimension{0, 0, 1, 0, 0, 0, 0}
+	current     = Dimension{0, 0, 0, 1, 0, 0, 0}
+	temperature = Dimension{0, 0, 0, 0, 1, 0, 0}
+	amount      = Dimension{0, 0, 0, 0, 0, 1, 0}
+	luminosity  = Dimension{0, 0, 0, 0, 0, 0, 1}
+
+	area      = Dimension{2, 0, 0, 0, 0, 0, 0}
+	volume    = Dimension{3, 0, 0, 0, 0, 0, 0}
+	frequency = Dimension{0, 0, -1, 0, 0, 0, 0}
+	velocity  = Dimension{1, 0, -1, 0, 0, 0, 0}
+	force     = Dimension{1, 1, -2, 0, 0, 0, 0}
+	energy    = Dimension{2, 1, -2, 0, 0, 0, 0}
+	power     = Dimension{2, 1, -3, 0, 0, 0, 0}
+	pressure  = Dimension{-1, 1, -2, 0, 0, 0, 0}
+	charge    = Dimension{0, 0, 1, 1, 0, 0, 0}
+	voltage   = Dimension{2, 1, -3, -1, 0, 0, 0}
+)
+
+// set container that registers all units from init
+var allUnits = map[string]*definition{}
+
+func registerUnit(name string, factor float64, dim Dimension) {
+	registerOffsetUnit(name, factor, 0, dim)
+}
+
+func registerOffsetUnit(name string, factor, offset float64, dim Dimension) {
+	if _, ok := allUnits[name]; ok {
+		panic(fmt.Sprintf("unit '%s' already registered", name))
+	}
+	allUnits[name] = &definition{
+		name:   name,
+		factor: factor,
+		offset: offset,
+		dim:    dim,
+	}
+}
+
+func init() {
+	// Length
+	registerUnit("m", 1, length)
+	registerUnit("km", 1e3, length)
+	registerUnit("cm", 1e-2, length)
+	registerUnit("mm", 1e-3, length)
+	registerUnit("um", 1e-6, length)
+	registerUnit("nm", 1e-9, length)
+	registerUnit("inch", 0.0254, length)
+	registerUnit("ft", 0.3048, length)
+	registerUnit("yd", 0.9144, length)
+	registerUnit("mi", 1609.344, length)
+	registerUnit("nmi", 1852, length)
+
+	// Mass
+	registerUnit("kg", 1, mass)
+	registerUnit("g", 1e-3, mass)
+	registerUnit("mg", 1e-6, mass)
+	registerUnit("t", 1e3, mass)
+	registerUnit("lb", 0.45359237, mass)
+	registerUnit("oz", 0.028349523125, mass)
+
+	// Time
+	registerUnit("s", 1, time)
+	registerUnit("ms", 1e-3, time)
+	registerUnit("us", 1e-6, time)
+	registerUnit("ns", 1e-9, time)
+	registerUnit("min", 60, time)
+	registerUnit("h", 3600, time)
+	registerUnit("day", 86400, time)
+	registerUnit("week", 604800, time)
+	registerUnit("year", 31557600, time) // Julian year of 365.25 days
+
+	// Electric current, amount of substance and luminous intensity
+	registerUnit("A", 1, current)
+	registerUnit("mA", 1e-3, current)
+	registerUnit("mol", 1, amount)
+	registerUnit("cd", 1, luminosity)
+
+	// Temperature, the offsets convert them to kelvin
+	registerUnit("K", 1, temperature)
+	registerOffsetUnit("degC", 1, 273.15, temperature)
+	registerOffsetUnit("degF", 5.0/9, 273.15-32*5.0/9, temperature)
+
+	// Area and volume
+	registerUnit("ha", 1e4, area)
+	registerUnit("acre", 4046.8564224, area)
+	registerUnit("L", 1e-3, volume)
+	registerUnit("mL", 1e-6, volume)
+	registerUnit("gal", 3.785411784e-3, volume) // US gallon
+
+	// Derived units
+	registerUnit("Hz", 1, frequency)
+	registerUnit("kHz", 1e3, frequency)
+	registerUnit("MHz", 1e6, frequency)
+	registerUnit("GHz", 1e9, frequency)
+	registerUnit("mph", 0.44704, velocity)
+	registerUnit("kn", 1852.0/3600, velocity)
+	registerUnit("N", 1, force)
+	registerUnit("lbf", 4.4482216152605, force)
+	registerUnit("J", 1, energy)
+	registerUnit("kJ", 1e3, energy)
+	registerUnit("cal", 4.184, energy)
+	registerUnit("kcal", 4184, energy)
+	registerUnit("Wh", 3600, energy)
+	registerUnit("kWh", 3.6e6, energy)
+	registerUnit("eV", 1.602176634e-19, energy)
+	registerUnit("W", 1, power)
+	registerUnit("kW", 1e3, power)
+	registerUnit("MW", 1e6, power)
+	registerUnit("hp", 745.69987158227022, power)
+	registerUnit("Pa", 1, pressure)
+	registerUnit("kPa", 1e3, pressure)
+	registerUnit("bar", 1e5, pressure)
+	registerUnit("atm", 101325, pressure)
+	registerUnit("psi", 6894.757293168361, pressure)
+	registerUnit("C", 1, charge)
+	registerUnit("V", 1, voltage)
+}
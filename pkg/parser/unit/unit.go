@@ -0,0 +1,197 @@
+package unit
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrIncompatibleUnits = fmt.Errorf("incompatible units")
+	ErrUnitNotFound      = fmt.Errorf("unit not found")
+)
+
+// Dimension holds the exponents of the SI base quantities:
+// length, mass, time, current, temperature, amount and luminosity
+type Dimension [7]int
+
+func (d Dimension) add(other Dimension, power int) Dimension {
+	for i := range d {
+		d[i] += other[i] * power
+	}
+	return d
+}
+
+// definition is a named unit from the table
+type definition struct {
+	name string
+	// factor converts the unit to the SI base units
+	factor float64
+	// offset is added after the factor for the temperatures like degC
+	offset float64
+	dim    Dimension
+}
+
+// term is a named unit raised to an integer power like s**-2
+type term struct {
+	def   *definition
+	power int
+}
+
+// Unit is a product of named units with integer powers like km/h or
+// kg*m/s**2, the zero value is the unit of dimensionless numbers.
+type Unit struct {
+	terms []term
+}
+
+// Lookup returns the unit with the name from the table
+func Lookup(name string) (Unit, error) {
+	def, ok := allUnits[name]
+	if !ok {
+		return Unit{}, fmt.Errorf("%w: '%s'", ErrUnitNotFound, name)
+	}
+
+	return Unit{terms: []term{{def: def, power: 1}}}, nil
+}
+
+// IsUnit checks if the name is in the unit table
+func IsUnit(name string) bool {
+	_, ok := allUnits[name]
+	return ok
+}
+
+// Names returns the sorted names of all units
+func Names() []string {
+	names := make([]string, 0, len(allUnits))
+	for name := range allUnits {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// Mul returns the product of the units, the powers
+// of the same named units are added like m*m = m**2
+func (u Unit) Mul(other Unit) Unit {
+	return u.combine(other, 1)
+}
+
+// Div returns the quotient of the units
+func (u Unit) Div(other Unit) Unit {
+	return u.combine(other, -1)
+}
+
+// Pow returns the unit raised to the power
+func (u Unit) Pow(power int) Unit {
+	terms := make([]term, 0, len(u.terms))
+	if power != 0 {
+		for _, t := range u.terms {
+			terms = append(terms, term{def: t.def, power: t.power * power})
+		}
+	}
+
+	return Unit{terms: terms}
+}
+
+func (u Unit) combine(other Unit, sign int) Unit {
+	terms := slices.Clone(u.terms)
+	for _, t := range other.terms {
+		i := slices.IndexFunc(terms, func(x term) bool { return x.def == t.def })
+		if i < 0 {
+			terms = append(terms, term{def: t.def, power: t.power * sign})
+			continue
+		}
+
+		terms[i].power += t.power * sign
+		if terms[i].power == 0 {
+			terms = slices.Delete(terms, i, i+1)
+		}
+	}
+
+	return Unit{terms: terms}
+}
+
+// Factor returns the factor to convert the unit to the SI base units
+func (u Unit) Factor() float64 {
+	factor := 1.0
+	for _, t := range u.terms {
+		factor *= math.Pow(t.def.factor, float64(t.power))
+	}
+	return factor
+}
+
+func (u Unit) Dimension() Dimension {
+	var dim Dimension
+	for _, t := range u.terms {
+		dim = dim.add(t.def.dim, t.power)
+	}
+	return dim
+}
+
+// IsDimensionless checks if the unit has no dimension like km/m
+func (u Unit) IsDimensionless() bool {
+	return u.Dimension() == Dimension{}
+}
+
+// Compatible checks if the units have the same dimension
+func (u Unit) Compatible(other Unit) bool {
+	return u.Dimension() == other.Dimension()
+}
+
+// offset returns the offset of a single unit like degC,
+// the offset of a compound unit like degC/s is ignored.
+func (u Unit) offset() float64 {
+	if len(u.terms) == 1 && u.terms[0].power == 1 {
+		return u.terms[0].def.offset
+	}
+	return 0
+}
+
+// Convert converts the amount from the unit to the other unit,
+// the offsets are only applied to the units like degC and degF alone.
+func Convert(amount float64, from, to Unit) (float64, error) {
+	if !from.Compatible(to) {
+		return 0, fmt.Errorf("%w: cannot convert '%s' to '%s'", ErrIncompatibleUnits, from, to)
+	}
+
+	si := amount*from.Factor() + from.offset()
+	return (si - to.offset()) / to.Factor(), nil
+}
+
+// String returns the unit like kg*m/s**2, 1/s without numerator,
+// or 1 for the dimensionless unit
+func (u Unit) String() string {
+	if len(u.terms) == 0 {
+		return "1"
+	}
+
+	var num, denom []string
+	for _, t := range u.terms {
+		power := t.power
+		if power < 0 {
+			power = -power
+		}
+
+		text := t.def.name
+		if power != 1 {
+			text += "**" + strconv.Itoa(power)
+		}
+
+		if t.power > 0 {
+			num = append(num, text)
+		} else {
+			denom = append(denom, text)
+		}
+	}
+
+	if len(denom) == 0 {
+		return strings.Join(num, "*")
+	}
+	if len(num) == 0 {
+		num = append(num, "1")
+	}
+	return strings.Join(num, "*") + "/" + strings.Join(denom, "/")
+}
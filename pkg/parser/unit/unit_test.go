@@ -0,0 +1,133 @@
+package unit_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"simplecalc/pkg/parser/unit"
+)
+
+func mustLookup(t *testing.T, name string) unit.Unit {
+	t.Helper()
+	u, err := unit.Lookup(name)
+	if err != nil {
+		t.Fatalf("Lookup(%q) error = %v", name, err)
+	}
+	return u
+}
+
+func TestUnit_String(t *testing.T) {
+	m := mustLookup(t, "m")
+	s := mustLookup(t, "s")
+	kg := mustLookup(t, "kg")
+
+	tests := []struct {
+		name string
+		unit unit.Unit
+		want string
+	}{
+		{
+			name: "single unit",
+			unit: m,
+			want: "m",
+		},
+		{
+			name: "quotient",
+			unit: m.Div(s),
+			want: "m/s",
+		},
+		{
+			name: "same units are combined",
+			unit: kg.Mul(m).Div(s).Div(s),
+			want: "kg*m/s**2",
+		},
+		{
+			name: "units are cancelled",
+			unit: m.Mul(s).Div(m),
+			want: "s",
+		},
+		{
+			name: "without numerator",
+			unit: s.Pow(-1),
+			want: "1/s",
+		},
+		{
+			name: "dimensionless",
+			unit: m.Pow(0),
+			want: "1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.unit.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  float64
+		from    unit.Unit
+		to      unit.Unit
+		want    float64
+		wantErr error
+	}{
+		{
+			name:   "length",
+			amount: 1,
+			from:   mustLookup(t, "mi"),
+			to:     mustLookup(t, "km"),
+			want:   1.609344,
+		},
+		{
+			name:   "compound units",
+			amount: 36,
+			from:   mustLookup(t, "km").Div(mustLookup(t, "h")),
+			to:     mustLookup(t, "m").Div(mustLookup(t, "s")),
+			want:   10,
+		},
+		{
+			name:   "derived unit",
+			amount: 1,
+			from:   mustLookup(t, "kWh"),
+			to:     mustLookup(t, "J"),
+			want:   3.6e6,
+		},
+		{
+			name:   "temperature with offset",
+			amount: 100,
+			from:   mustLookup(t, "degC"),
+			to:     mustLookup(t, "degF"),
+			want:   212,
+		},
+		{
+			name:    "incompatible units",
+			amount:  1,
+			from:    mustLookup(t, "m"),
+			to:      mustLookup(t, "kg"),
+			wantErr: unit.ErrIncompatibleUnits,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := unit.Convert(tt.amount, tt.from, tt.to)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Convert() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Convert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookup_NotFound(t *testing.T) {
+	_, err := unit.Lookup("furlong")
+	if !errors.Is(err, unit.ErrUnitNotFound) {
+		t.Errorf("Lookup() error = %v, want %v", err, unit.ErrUnitNotFound)
+	}
+}
@@ -0,0 +1,60 @@
+package value
+
+import (
+	"math"
+	"math/cmplx"
+	"strconv"
+)
+
+// Complex is a complex number with a non-zero imaginary part like 3+4i,
+// use FromComplex to create it from the result of an operation.
+type Complex complex128
+
+// FromComplex returns the complex number, or Float if the imaginary part
+// is zero, so the results like (1+i)*(1-i) are real numbers again.
+func FromComplex(c complex128) Value {
+	if imag(c) == 0 {
+		return Float(real(c))
+	}
+
+	return Complex(c)
+}
+
+func (v Complex) Kind() Kind {
+	return KindComplex
+}
+
+// String returns the rectangular form like 3+4i, 3-4i or 4i
+func (v Complex) String() string {
+	re, im := real(v), imag(v)
+	imText := strconv.FormatFloat(im, 'f', -1, 64) + "i"
+	if re == 0 {
+		return imText
+	}
+
+	reText := strconv.FormatFloat(re, 'f', -1, 64)
+	if im < 0 || math.IsNaN(im) {
+		return reText + imText
+	}
+	return reText + "+" + imText
+}
+
+// Polar returns the polar form like 5∠0.9272952180016122,
+// the magnitude and the phase in radians.
+func (v Complex) Polar() string {
+	r, theta := cmplx.Polar(complex128(v))
+	return strconv.FormatFloat(r, 'f', -1, 64) + "∠" + strconv.FormatFloat(theta, 'f', -1, 64)
+}
+
+// ToComplex converts the number to complex128
+func ToComplex(v Value) (complex128, error) {
+	if c, ok := v.(Complex); ok {
+		return complex128(c), nil
+	}
+
+	x, err := ToFloat(v)
+	if err != nil {
+		return 0, err
+	}
+	return complex(x, 0), nil
+}
@@ -0,0 +1,258 @@
+package value
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// DefaultDigits is the default number of significant decimal digits
+// of the exact mode, used when a rational number has to be approximated.
+const DefaultDigits = 50
+
+// guardBits are the extra bits of big.Float to hide rounding errors
+// from the last displayed digit
+const guardBits = 16
+
+// PrecisionBits returns the mantissa precision of big.Float
+// to hold the number of significant decimal digits.
+func PrecisionBits(digits uint) uint {
+	return uint(math.Ceil(float64(digits)*math.Log2(10))) + guardBits
+}
+
+// Rational is an exact number from the exact mode like 1/3 or 0.1
+type Rational struct {
+	rat    *big.Rat
+	digits uint
+}
+
+// NewRational creates a rational number which is approximated
+// with the number of significant decimal digits if needed.
+// The rational number must not be modified after creation.
+func NewRational(rat *big.Rat, digits uint) Rational {
+	return Rational{
+		rat:    rat,
+		digits: digits,
+	}
+}
+
+func (v Rational) Kind() Kind {
+	return KindRational
+}
+
+// Rat returns the underlying rational number, which must not be modified
+func (v Rational) Rat() *big.Rat {
+	return v.rat
+}
+
+func (v Rational) Digits() uint {
+	return v.digits
+}
+
+// String returns the exact decimal representation of the rational number
+// if it has finite decimal digits like 0.3, otherwise it returns the number
+// rounded to the significant decimal digits like 0.33333...
+func (v Rational) String() string {
+	if v.rat.IsInt() {
+		return v.rat.Num().String()
+	}
+
+	if scale, ok := decimalScale(v.rat.Denom()); ok {
+		return v.rat.FloatString(scale)
+	}
+
+	return NewBigFloat(new(big.Float).SetPrec(PrecisionBits(v.digits)).SetRat(v.rat), v.digits).String()
+}
+
+// decimalScale returns the number of decimal digits of 1/denom
+// if it has finite decimal digits, only when denom = 2^a * 5^b.
+func decimalScale(denom *big.Int) (int, bool) {
+	d := new(big.Int).Set(denom)
+	two, five := big.NewInt(2), big.NewInt(5)
+	mod := new(big.Int)
+	scale2, scale5 := 0, 0
+	for {
+		q, r := new(big.Int).QuoRem(d, two, mod)
+		if r.Sign() != 0 {
+			break
+		}
+		d = q
+		scale2++
+	}
+	for {
+		q, r := new(big.Int).QuoRem(d, five, mod)
+		if r.Sign() != 0 {
+			break
+		}
+		d = q
+		scale5++
+	}
+
+	return max(scale2, scale5), d.Cmp(big.NewInt(1)) == 0
+}
+
+// BigFloat is an approximate number from the exact mode like sqrt(2)
+type BigFloat struct {
+	float  *big.Float
+	digits uint
+}
+
+// NewBigFloat creates a big float number displayed with the number
+// of significant decimal digits. The number must not be modified after creation.
+func NewBigFloat(float *big.Float, digits uint) BigFloat {
+	return BigFloat{
+		float:  float,
+		digits: digits,
+	}
+}
+
+// NewBigFloatFromFloat creates a big float number from float64,
+// only the digits supported by float64 are displayed.
+func NewBigFloatFromFloat(x float64, digits uint) BigFloat {
+	return NewBigFloat(new(big.Float).SetFloat64(x), digits)
+}
+
+func (v BigFloat) Kind() Kind {
+	return KindBigFloat
+}
+
+// Float returns the underlying big float number, which must not be modified
+func (v BigFloat) Float() *big.Float {
+	return v.float
+}
+
+func (v BigFloat) Digits() uint {
+	return v.digits
+}
+
+func (v BigFloat) String() string {
+	if v.float.IsInf() {
+		return v.float.String()
+	}
+
+	// Don't display more digits than the mantissa can hold
+	digits := min(int(v.digits), int(float64(v.float.Prec())*math.Log10(2)))
+
+	// Prefer the decimal notation for small numbers like 0.0000001,
+	// keep the scientific notation for the numbers too large to display
+	text := v.float.Text('g', digits)
+	if _, exp, ok := strings.Cut(text, "e"); ok {
+		if e, err := strconv.Atoi(exp); err == nil && e < 0 {
+			text = v.float.Text('f', digits-1-e)
+			return strings.TrimRight(strings.TrimRight(text, "0"), ".")
+		}
+	}
+
+	return text
+}
+
+// ToBigFloat converts the number to big float with the precision
+// of the number of significant decimal digits
+func ToBigFloat(v Value, digits uint) (*big.Float, error) {
+	z := new(big.Float).SetPrec(PrecisionBits(digits))
+	switch v := v.(type) {
+	case BigFloat:
+		return z.Set(v.float), nil
+	case Rational:
+		return z.SetRat(v.rat), nil
+	default:
+		x, err := ToFloat(v)
+		if err != nil {
+			return nil, err
+		}
+		if math.IsNaN(x) {
+			return nil, ErrNaN
+		}
+		return z.SetFloat64(x), nil
+	}
+}
+
+// ToRational converts a boolean or a rational number to big.Rat
+func ToRational(v Value) (*big.Rat, error) {
+	switch v := v.(type) {
+	case Rational:
+		return v.rat, nil
+	case Bool:
+		if v {
+			return big.NewRat(1, 1), nil
+		}
+		return new(big.Rat), nil
+	default:
+		return nil, invalidType(v, KindRational)
+	}
+}
+
+// Digits returns the number of significant decimal digits of the
+// exact number, or 0 if the value is not from the exact mode.
+func Digits(v Value) uint {
+	switch v := v.(type) {
+	case Rational:
+		return v.digits
+	case BigFloat:
+		return v.digits
+	default:
+		return 0
+	}
+}
+
+// Floor returns the greatest integer less than or equal to the number
+func Floor(v Value) (Value, error) {
+	return round(v, math.Floor, func(n, d *big.Int) *big.Int {
+		// Euclidean division rounds down for the positive denominator
+		return new(big.Int).Div(n, d)
+	})
+}
+
+// Ceil returns the least integer greater than or equal to the number
+func Ceil(v Value) (Value, error) {
+	return round(v, math.Ceil, func(n, d *big.Int) *big.Int {
+		q := new(big.Int).Div(n, d)
+		if new(big.Int).Mul(q, d).Cmp(n) != 0 {
+			q.Add(q, big.NewInt(1))
+		}
+		return q
+	})
+}
+
+// Trunc returns the integer part of the number
+func Trunc(v Value) (Value, error) {
+	return round(v, math.Trunc, func(n, d *big.Int) *big.Int {
+		return new(big.Int).Quo(n, d)
+	})
+}
+
+// Round returns the nearest integer, rounding half away from zero
+func Round(v Value) (Value, error) {
+	return round(v, math.Round, func(n, d *big.Int) *big.Int {
+		// (2|n| + d) / 2d with the sign of n
+		twice := new(big.Int).Lsh(new(big.Int).Abs(n), 1)
+		q := new(big.Int).Quo(twice.Add(twice, d), new(big.Int).Lsh(d, 1))
+		if n.Sign() < 0 {
+			q.Neg(q)
+		}
+		return q
+	})
+}
+
+// round rounds the number to an integer with the float function,
+// or with the integer division of the numerator and the denominator
+func round(v Value, float func(float64) float64, div func(n, d *big.Int) *big.Int) (Value, error) {
+	switch v := v.(type) {
+	case Rational:
+		return NewRational(new(big.Rat).SetInt(div(v.rat.Num(), v.rat.Denom())), v.digits), nil
+	case BigFloat:
+		if v.float.IsInf() {
+			return v, nil
+		}
+		r, _ := v.float.Rat(nil)
+		i := div(r.Num(), r.Denom())
+		return NewBigFloat(new(big.Float).SetPrec(v.float.Prec()).SetInt(i), v.digits), nil
+	default:
+		x, err := ToFloat(v)
+		if err != nil {
+			return nil, err
+		}
+		return Float(float(x)), nil
+	}
+}
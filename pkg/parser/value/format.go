@@ -0,0 +1,83 @@
+package value
+
+import (
+	"fmt"
+	"math/big"
+)
+
+var ErrInvalidFormat = fmt.Errorf("invalid format")
+
+// Format is how a rational or complex number is displayed
+type Format uint8
+
+const (
+	// FormatDecimal displays 3/2 as 1.5, and complex numbers
+	// in the rectangular form like 3+4i
+	FormatDecimal Format = iota
+	// FormatFraction displays 3/2 as 3/2
+	FormatFraction
+	// FormatMixed displays 3/2 as 1 1/2
+	FormatMixed
+	// FormatPolar displays 3+4i as 5∠0.9272952180016122
+	FormatPolar
+)
+
+func (f Format) String() string {
+	switch f {
+	case FormatDecimal:
+		return "decimal"
+	case FormatFraction:
+		return "fraction"
+	case FormatMixed:
+		return "mixed"
+	case FormatPolar:
+		return "polar"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseFormat returns the format with the name from Format.String
+func ParseFormat(name string) (Format, error) {
+	for _, f := range []Format{FormatDecimal, FormatFraction, FormatMixed, FormatPolar} {
+		if f.String() == name {
+			return f, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: '%s', must be decimal, fraction, mixed or polar", ErrInvalidFormat, name)
+}
+
+// FormatValue displays a rational or complex number with the format,
+// also the elements of a list, the other values are always displayed
+// with their String method.
+func FormatValue(v Value, f Format) string {
+	if l, ok := v.(List); ok {
+		return l.format(func(elem Value) string {
+			return FormatValue(elem, f)
+		})
+	}
+
+	if c, ok := v.(Complex); ok && f == FormatPolar {
+		return c.Polar()
+	}
+
+	r, ok := v.(Rational)
+	if !ok || r.rat.IsInt() {
+		return v.String()
+	}
+
+	switch f {
+	case FormatFraction:
+		return r.rat.String()
+	case FormatMixed:
+		// The whole part is truncated, the sign goes with it like -1 1/2
+		whole, rem := new(big.Int).QuoRem(r.rat.Num(), r.rat.Denom(), new(big.Int))
+		if whole.Sign() == 0 {
+			return r.rat.String()
+		}
+		return fmt.Sprintf("%s %s/%s", whole, rem.Abs(rem), r.rat.Denom())
+	default:
+		return r.String()
+	}
+}
@@ -0,0 +1,31 @@
+package value
+
+import (
+	"fmt"
+	"strings"
+)
+
+var ErrLengthMismatch = fmt.Errorf("length mismatch")
+
+// List is a series of values like [1, 2, 3], which must not be modified
+// after creation because the variables may share it.
+type List []Value
+
+func (v List) Kind() Kind {
+	return KindList
+}
+
+func (v List) String() string {
+	return v.format(func(elem Value) string {
+		return elem.String()
+	})
+}
+
+func (v List) format(fn func(Value) string) string {
+	elems := make([]string, 0, len(v))
+	for _, elem := range v {
+		elems = append(elems, fn(elem))
+	}
+
+	return "[" + strings.Join(elems, ", ") + "]"
+}
@@ -0,0 +1,117 @@
+package value
+
+import (
+	"fmt"
+	"strings"
+)
+
+var ErrShapeMismatch = fmt.Errorf("shape mismatch")
+
+// Shape is the number of rows and columns of a matrix
+type Shape struct {
+	Rows int
+	Cols int
+}
+
+func (s Shape) String() string {
+	return fmt.Sprintf("%dx%d", s.Rows, s.Cols)
+}
+
+// ShapeError reports the shapes of the matrices that don't fit the operation,
+// it matches ErrShapeMismatch with errors.Is.
+type ShapeError struct {
+	Op     string
+	Shapes []Shape
+}
+
+func (e *ShapeError) Error() string {
+	shapes := make([]string, 0, len(e.Shapes))
+	for _, s := range e.Shapes {
+		shapes = append(shapes, s.String())
+	}
+
+	return fmt.Sprintf("%s: '%s' of %s", ErrShapeMismatch, e.Op, strings.Join(shapes, " and "))
+}
+
+func (e *ShapeError) Unwrap() error {
+	return ErrShapeMismatch
+}
+
+// Matrix is the rows of a list like [[1, 2], [3, 4]],
+// all rows have the same number of columns.
+type Matrix [][]Value
+
+// ToMatrix converts a list of lists with the same length to a matrix,
+// a list of numbers like [1, 2] is a matrix with one row.
+func ToMatrix(v Value) (Matrix, error) {
+	list, ok := v.(List)
+	if !ok {
+		return nil, invalidType(v, KindList)
+	}
+	if len(list) == 0 {
+		return nil, fmt.Errorf("%w: empty list is not a matrix", ErrInvalidType)
+	}
+
+	if _, ok := list[0].(List); !ok {
+		for i, elem := range list {
+			if elem.Kind() == KindList {
+				return nil, fmt.Errorf("%w: element %d of vector is list", ErrInvalidType, i)
+			}
+		}
+		return Matrix{list}, nil
+	}
+
+	m := make(Matrix, 0, len(list))
+	for i, elem := range list {
+		row, ok := elem.(List)
+		if !ok {
+			return nil, fmt.Errorf("%w: row %d of matrix is %s", ErrInvalidType, i, elem.Kind())
+		}
+		if len(row) == 0 {
+			if i == 0 {
+				return nil, fmt.Errorf("%w: empty matrix", ErrShapeMismatch)
+			}
+			return nil, fmt.Errorf("%w: row %d of matrix is empty", ErrShapeMismatch, i)
+		}
+		if len(row) != len(list[0].(List)) {
+			return nil, fmt.Errorf("%w: row %d of matrix has %d elements instead of %d",
+				ErrInvalidType, i, len(row), len(list[0].(List)))
+		}
+		m = append(m, row)
+	}
+
+	return m, nil
+}
+
+func (m Matrix) Shape() Shape {
+	return Shape{Rows: len(m), Cols: len(m[0])}
+}
+
+// IsSquare checks if the matrix has the same number of rows and columns
+func (m Matrix) IsSquare() bool {
+	return len(m) == len(m[0])
+}
+
+// Transpose returns the matrix with the rows and columns swapped
+func (m Matrix) Transpose() Matrix {
+	t := make(Matrix, 0, len(m[0]))
+	for j := range m[0] {
+		row := make([]Value, 0, len(m))
+		for i := range m {
+			row = append(row, m[i][j])
+		}
+		t = append(t, row)
+	}
+
+	return t
+}
+
+// List returns the matrix as a list of rows
+func (m Matrix) List() List {
+	list := make(List, 0, len(m))
+	for _, row := range m {
+		list = append(list, List(row))
+	}
+
+	return list
+}
@@ -0,0 +1,56 @@
+package value
+
+import (
+	"strconv"
+
+	"simplecalc/pkg/parser/unit"
+)
+
+// Quantity is a float64 amount with a unit like 5 km or 9.81 m/s**2
+type Quantity struct {
+	amount float64
+	unit   unit.Unit
+}
+
+// NewQuantity returns the quantity, or Float if the unit is dimensionless,
+// so the results like 3 km / 500 m are numbers again.
+func NewQuantity(amount float64, u unit.Unit) Value {
+	if u.IsDimensionless() {
+		return Float(amount * u.Factor())
+	}
+
+	return Quantity{
+		amount: amount,
+		unit:   u,
+	}
+}
+
+func (v Quantity) Kind() Kind {
+	return KindQuantity
+}
+
+func (v Quantity) Amount() float64 {
+	return v.amount
+}
+
+func (v Quantity) Unit() unit.Unit {
+	return v.unit
+}
+
+func (v Quantity) String() string {
+	return strconv.FormatFloat(v.amount, 'f', -1, 64) + " " + v.unit.String()
+}
+
+// ToQuantity converts a number to a quantity, a number without unit
+// is a quantity with the dimensionless unit.
+func ToQuantity(v Value) (Quantity, error) {
+	if q, ok := v.(Quantity); ok {
+		return q, nil
+	}
+
+	x, err := ToFloat(v)
+	if err != nil {
+		return Quantity{}, err
+	}
+	return Quantity{amount: x}, nil
+}
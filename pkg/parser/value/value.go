@@ -0,0 +1,260 @@
+package value
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+var (
+	ErrInvalidType = fmt.Errorf("invalid type")
+	ErrNaN         = fmt.Errorf("result is not a number")
+)
+
+type Kind uint8
+
+const (
+	KindFloat Kind = iota
+	KindBool
+	KindRational
+	KindBigFloat
+	KindComplex
+	KindQuantity
+	KindList
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindFloat:
+		return "float"
+	case KindBool:
+		return "bool"
+	case KindRational:
+		return "rational"
+	case KindBigFloat:
+		return "bigfloat"
+	case KindComplex:
+		return "complex"
+	case KindQuantity:
+		return "quantity"
+	case KindList:
+		return "list"
+	default:
+		return "unknown"
+	}
+}
+
+// Value is the result of evaluating an expression
+type Value interface {
+	Kind() Kind
+	String() string
+}
+
+type Float float64
+
+func (v Float) Kind() Kind {
+	return KindFloat
+}
+
+func (v Float) String() string {
+	// Minimize digits and no scientific notation
+	// if number is greater than 1e6-1 or less than -1e6+1
+	return strconv.FormatFloat(float64(v), 'f', -1, 64)
+}
+
+// Bool is the result of comparison and logical operators
+type Bool bool
+
+func (v Bool) Kind() Kind {
+	return KindBool
+}
+
+func (v Bool) String() string {
+	if v {
+		return "true"
+	}
+	return "false"
+}
+
+// ToFloat converts the value to float64,
+// a boolean is converted to 1 for true and 0 for false.
+func ToFloat(v Value) (float64, error) {
+	switch v := v.(type) {
+	case Float:
+		return float64(v), nil
+	case Bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case Rational:
+		x, _ := v.rat.Float64()
+		return x, nil
+	case BigFloat:
+		x, _ := v.float.Float64()
+		return x, nil
+	default:
+		return 0, invalidType(v, KindFloat)
+	}
+}
+
+func invalidType(v Value, want Kind) error {
+	return fmt.Errorf("%w: %s is not %s", ErrInvalidType, v.Kind(), want)
+}
+
+// Truthy checks if the value is considered true in a logical context,
+// any number other than 0 and NaN is true, so is any non-empty list.
+func Truthy(v Value) bool {
+	switch v := v.(type) {
+	case Bool:
+		return bool(v)
+	case Float:
+		return v != 0 && !math.IsNaN(float64(v))
+	case Rational:
+		return v.rat.Sign() != 0
+	case BigFloat:
+		return v.float.Sign() != 0
+	case Complex:
+		return v != 0
+	case Quantity:
+		return v.amount != 0
+	case List:
+		return len(v) > 0
+	default:
+		return false
+	}
+}
+
+// rank orders the kinds of numbers, the number of lower rank
+// is promoted to the kind of the other one for arithmetic.
+// Float ranks above the exact numbers because it can't be exact again,
+// and Complex has the highest rank as the real numbers are complex.
+func rank(k Kind) int {
+	switch k {
+	case KindBool:
+		return 0
+	case KindRational:
+		return 1
+	case KindBigFloat:
+		return 2
+	case KindFloat:
+		return 3
+	case KindComplex:
+		return 4
+	default:
+		return -1
+	}
+}
+
+// Promote converts two numbers to the same kind, two booleans are
+// promoted to Float, so they are 1 and 0 in arithmetic.
+func Promote(x, y Value) (Value, Value, error) {
+	if rank(x.Kind()) < 0 {
+		return nil, nil, fmt.Errorf("%w: %s is not a number", ErrInvalidType, x.Kind())
+	}
+	if rank(y.Kind()) < 0 {
+		return nil, nil, fmt.Errorf("%w: %s is not a number", ErrInvalidType, y.Kind())
+	}
+
+	kind := x.Kind()
+	if rank(y.Kind()) > rank(kind) {
+		kind = y.Kind()
+	}
+	if kind == KindBool {
+		kind = KindFloat
+	}
+
+	digits := max(Digits(x), Digits(y))
+	px, err := convert(x, kind, digits)
+	if err != nil {
+		return nil, nil, err
+	}
+	py, err := convert(y, kind, digits)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return px, py, nil
+}
+
+func convert(v Value, kind Kind, digits uint) (Value, error) {
+	if v.Kind() == kind {
+		return v, nil
+	}
+
+	switch kind {
+	case KindFloat:
+		x, err := ToFloat(v)
+		return Float(x), err
+	case KindRational:
+		r, err := ToRational(v)
+		return NewRational(r, digits), err
+	case KindBigFloat:
+		f, err := ToBigFloat(v, digits)
+		return NewBigFloat(f, digits), err
+	case KindComplex:
+		c, err := ToComplex(v)
+		return Complex(c), err
+	default:
+		return nil, invalidType(v, kind)
+	}
+}
+
+// Compare compares two numbers and returns -1, 0 or +1,
+// NaN is not comparable and must be checked before,
+// complex numbers are not ordered.
+func Compare(x, y Value) (int, error) {
+	x, y, err := Promote(x, y)
+	if err != nil {
+		return 0, err
+	}
+
+	switch x := x.(type) {
+	case Float:
+		return cmp.Compare(x, y.(Float)), nil
+	case Rational:
+		return x.rat.Cmp(y.(Rational).rat), nil
+	case BigFloat:
+		return x.float.Cmp(y.(BigFloat).float), nil
+	default:
+		return 0, invalidType(x, KindFloat)
+	}
+}
+
+// Negate returns the number with the opposite sign
+func Negate(v Value) (Value, error) {
+	switch v := v.(type) {
+	case Rational:
+		return NewRational(new(big.Rat).Neg(v.rat), v.digits), nil
+	case BigFloat:
+		return NewBigFloat(new(big.Float).Neg(v.float), v.digits), nil
+	case Complex:
+		return -v, nil
+	case Quantity:
+		return Quantity{amount: -v.amount, unit: v.unit}, nil
+	case List:
+		result := make(List, 0, len(v))
+		for _, elem := range v {
+			negated, err := Negate(elem)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, negated)
+		}
+		return result, nil
+	default:
+		x, err := ToFloat(v)
+		if err != nil {
+			return nil, err
+		}
+		return Float(-x), nil
+	}
+}
+
+// IsNaN checks if the value is a float64 NaN
+func IsNaN(v Value) bool {
+	x, ok := v.(Float)
+	return ok && math.IsNaN(float64(x))
+}
@@ -0,0 +1,273 @@
+package value_test
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+
+	"simplecalc/pkg/parser/value"
+)
+
+func TestValue_String(t *testing.T) {
+	tests := []struct {
+		name  string
+		value value.Value
+		want  string
+	}{
+		{
+			name:  "integer",
+			value: value.Float(42),
+			want:  "42",
+		},
+		{
+			name:  "large number without scientific notation",
+			value: value.Float(12345678),
+			want:  "12345678",
+		},
+		{
+			name:  "decimal",
+			value: value.Float(-0.25),
+			want:  "-0.25",
+		},
+		{
+			name:  "true",
+			value: value.Bool(true),
+			want:  "true",
+		},
+		{
+			name:  "false",
+			value: value.Bool(false),
+			want:  "false",
+		},
+		{
+			name:  "rational integer",
+			value: value.NewRational(new(big.Rat).SetFrac64(84, 2), 10),
+			want:  "42",
+		},
+		{
+			name:  "rational with finite decimal",
+			value: value.NewRational(big.NewRat(-3, 8), 10),
+			want:  "-0.375",
+		},
+		{
+			name:  "rational approximated with digits",
+			value: value.NewRational(big.NewRat(2, 3), 10),
+			want:  "0.6666666667",
+		},
+		{
+			name:  "big float with digits",
+			value: value.NewBigFloat(big.NewFloat(1234.5678), 6),
+			want:  "1234.57",
+		},
+		{
+			name:  "small big float without scientific notation",
+			value: value.NewBigFloat(big.NewFloat(0.000125), 10),
+			want:  "0.000125",
+		},
+		{
+			name:  "big float from float64 shows float64 digits only",
+			value: value.NewBigFloatFromFloat(0.1, 50),
+			want:  "0.1",
+		},
+		{
+			name:  "complex number",
+			value: value.Complex(3 - 4i),
+			want:  "3-4i",
+		},
+		{
+			name:  "imaginary number",
+			value: value.Complex(2.5i),
+			want:  "2.5i",
+		},
+		{
+			name:  "complex number with zero imaginary part is real",
+			value: value.FromComplex(3 + 0i),
+			want:  "3",
+		},
+		{
+			name:  "nested list",
+			value: value.List{value.Float(1), value.List{value.Bool(true), value.Complex(2i)}, value.List{}},
+			want:  "[1, [true, 2i], []]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.value.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruthy(t *testing.T) {
+	tests := []struct {
+		name  string
+		value value.Value
+		want  bool
+	}{
+		{
+			name:  "zero",
+			value: value.Float(0),
+			want:  false,
+		},
+		{
+			name:  "negative number",
+			value: value.Float(-1),
+			want:  true,
+		},
+		{
+			name:  "not a number",
+			value: value.Float(math.NaN()),
+			want:  false,
+		},
+		{
+			name:  "true",
+			value: value.Bool(true),
+			want:  true,
+		},
+		{
+			name:  "false",
+			value: value.Bool(false),
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := value.Truthy(tt.value); got != tt.want {
+				t.Errorf("Truthy(%v) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToFloat(t *testing.T) {
+	tests := []struct {
+		name  string
+		value value.Value
+		want  float64
+	}{
+		{
+			name:  "number",
+			value: value.Float(2.5),
+			want:  2.5,
+		},
+		{
+			name:  "true is one",
+			value: value.Bool(true),
+			want:  1,
+		},
+		{
+			name:  "false is zero",
+			value: value.Bool(false),
+			want:  0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := value.ToFloat(tt.value)
+			if err != nil {
+				t.Fatalf("ToFloat() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ToFloat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  value.Value
+		format value.Format
+		want   string
+	}{
+		{
+			name:   "fraction",
+			value:  value.NewRational(big.NewRat(6, 4), 10),
+			format: value.FormatFraction,
+			want:   "3/2",
+		},
+		{
+			name:   "negative fraction",
+			value:  value.NewRational(big.NewRat(-1, 3), 10),
+			format: value.FormatFraction,
+			want:   "-1/3",
+		},
+		{
+			name:   "integer is not a fraction",
+			value:  value.NewRational(big.NewRat(4, 2), 10),
+			format: value.FormatFraction,
+			want:   "2",
+		},
+		{
+			name:   "mixed number",
+			value:  value.NewRational(big.NewRat(7, 2), 10),
+			format: value.FormatMixed,
+			want:   "3 1/2",
+		},
+		{
+			name:   "negative mixed number",
+			value:  value.NewRational(big.NewRat(-7, 2), 10),
+			format: value.FormatMixed,
+			want:   "-3 1/2",
+		},
+		{
+			name:   "proper fraction as mixed number",
+			value:  value.NewRational(big.NewRat(-2, 3), 10),
+			format: value.FormatMixed,
+			want:   "-2/3",
+		},
+		{
+			name:   "decimal",
+			value:  value.NewRational(big.NewRat(7, 2), 10),
+			format: value.FormatDecimal,
+			want:   "3.5",
+		},
+		{
+			name:   "complex number in polar form",
+			value:  value.Complex(-2i),
+			format: value.FormatPolar,
+			want:   "2∠-1.5707963267948966",
+		},
+		{
+			name:   "complex number in rectangular form",
+			value:  value.Complex(1 + 1i),
+			format: value.FormatDecimal,
+			want:   "1+1i",
+		},
+		{
+			name:   "float is not a fraction",
+			value:  value.Float(0.5),
+			format: value.FormatFraction,
+			want:   "0.5",
+		},
+		{
+			name:   "list of fractions",
+			value:  value.List{value.NewRational(big.NewRat(1, 2), 10), value.Float(0.25)},
+			format: value.FormatFraction,
+			want:   "[1/2, 0.25]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := value.FormatValue(tt.value, tt.format); got != tt.want {
+				t.Errorf("FormatValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, f := range []value.Format{value.FormatDecimal, value.FormatFraction, value.FormatMixed, value.FormatPolar} {
+		got, err := value.ParseFormat(f.String())
+		if err != nil || got != f {
+			t.Errorf("ParseFormat(%q) = %v, %v, want %v", f.String(), got, err, f)
+		}
+	}
+
+	if _, err := value.ParseFormat("binary"); !errors.Is(err, value.ErrInvalidFormat) {
+		t.Errorf("ParseFormat() error = %v, want %v", err, value.ErrInvalidFormat)
+	}
+}
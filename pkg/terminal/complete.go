@@ -0,0 +1,100 @@
+package terminal
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const keyTab = '\t'
+
+// completer completes the identifier before the cursor by Tab,
+// pressing Tab again cycles through the candidates.
+type completer struct {
+	// commands are only completed at the beginning of the line
+	commands []string
+	// names returns the names like the variables and the functions
+	names func() []string
+
+	// matches are the candidates of the last completion, which is
+	// cycled if the line and the cursor are not changed after it.
+	matches []string
+	next    int
+	start   int
+	line    string
+	pos     int
+}
+
+func (c *completer) handleKey(line string, pos int, key rune) (string, int, bool) {
+	if key != keyTab {
+		c.matches = nil
+		return "", 0, false
+	}
+
+	if c.matches == nil || line != c.line || pos != c.pos {
+		c.start = identifierStart(line, pos)
+		first := strings.TrimSpace(line[:c.start]) == ""
+		c.matches = c.candidates(line[c.start:pos], first)
+		c.next = 0
+		if len(c.matches) == 0 {
+			c.matches = nil
+			return "", 0, false
+		}
+	}
+
+	match := c.matches[c.next]
+	c.next = (c.next + 1) % len(c.matches)
+
+	c.line = line[:c.start] + match + line[pos:]
+	c.pos = c.start + len(match)
+	return c.line, c.pos, true
+}
+
+// candidates returns the sorted names starting with the prefix
+func (c *completer) candidates(prefix string, first bool) []string {
+	if prefix == "" {
+		return nil
+	}
+
+	var names []string
+	if c.names != nil {
+		names = c.names()
+	}
+	if first {
+		names = append(names, c.commands...)
+	}
+
+	var matches []string
+	for _, name := range names {
+		if strings.HasPrefix(name, prefix) {
+			matches = append(matches, name)
+		}
+	}
+	slices.Sort(matches)
+
+	return slices.Compact(matches)
+}
+
+// identifierStart returns the start of the identifier before the cursor,
+// the digits like "2" of "2x" are not a part of it.
+func identifierStart(line string, pos int) int {
+	start := pos
+	for start > 0 {
+		r, size := utf8.DecodeLastRuneInString(line[:start])
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			break
+		}
+		start -= size
+	}
+
+	for start < pos {
+		r, size := utf8.DecodeRuneInString(line[start:])
+		if !unicode.IsDigit(r) {
+			break
+		}
+		start += size
+	}
+
+	return start
+}
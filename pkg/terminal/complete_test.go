@@ -0,0 +1,135 @@
+package terminal
+
+import (
+	"testing"
+)
+
+func TestCompleter_HandleKey(t *testing.T) {
+	c := &completer{
+		commands: []string{"help", "history", "exit"},
+		names: func() []string {
+			return []string{"sqrt", "sqval", "sin", "x_1", "pi", "hypot"}
+		},
+	}
+
+	tests := []struct {
+		name    string
+		line    string
+		pos     int
+		tabs    int
+		want    string
+		wantPos int
+		wantOk  bool
+	}{
+		{
+			name:    "single match",
+			line:    "si",
+			pos:     2,
+			tabs:    1,
+			want:    "sin",
+			wantPos: 3,
+			wantOk:  true,
+		},
+		{
+			name:    "first match",
+			line:    "sq",
+			pos:     2,
+			tabs:    1,
+			want:    "sqrt",
+			wantPos: 4,
+			wantOk:  true,
+		},
+		{
+			name:    "cycle",
+			line:    "sq",
+			pos:     2,
+			tabs:    2,
+			want:    "sqval",
+			wantPos: 5,
+			wantOk:  true,
+		},
+		{
+			name:    "cycle back",
+			line:    "sq",
+			pos:     2,
+			tabs:    3,
+			want:    "sqrt",
+			wantPos: 4,
+			wantOk:  true,
+		},
+		{
+			name:    "in the middle",
+			line:    "2 * sq(4) + 1",
+			pos:     6,
+			tabs:    1,
+			want:    "2 * sqrt(4) + 1",
+			wantPos: 8,
+			wantOk:  true,
+		},
+		{
+			name:    "after digits",
+			line:    "2x_",
+			pos:     3,
+			tabs:    1,
+			want:    "2x_1",
+			wantPos: 4,
+			wantOk:  true,
+		},
+		{
+			name:    "command at the beginning",
+			line:    " hi",
+			pos:     3,
+			tabs:    1,
+			want:    " history",
+			wantPos: 8,
+			wantOk:  true,
+		},
+		{
+			name:    "no command after the beginning",
+			line:    "1 + h",
+			pos:     5,
+			tabs:    1,
+			want:    "1 + hypot",
+			wantPos: 9,
+			wantOk:  true,
+		},
+		{
+			name:   "no match",
+			line:   "zz",
+			pos:    2,
+			tabs:   1,
+			wantOk: false,
+		},
+		{
+			name:   "no identifier",
+			line:   "1 + ",
+			pos:    4,
+			tabs:   1,
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Reset the cycle by another key
+			c.handleKey("", 0, 'a')
+
+			var got string
+			var gotPos int
+			var ok bool
+			line, pos := tt.line, tt.pos
+			for range tt.tabs {
+				got, gotPos, ok = c.handleKey(line, pos, keyTab)
+				if ok {
+					line, pos = got, gotPos
+				}
+			}
+
+			if ok != tt.wantOk {
+				t.Fatalf("handleKey() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if ok && (got != tt.want || gotPos != tt.wantPos) {
+				t.Errorf("handleKey() = %q, %d, want %q, %d", got, gotPos, tt.want, tt.wantPos)
+			}
+		})
+	}
+}
@@ -1,21 +1,139 @@
 package terminal
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"unicode"
+)
+
+// DefaultHistorySize is the maximum number of entries kept by default
+const DefaultHistorySize = 1000
+
+// HistoryEnv is the environment variable overriding the path of the history file
+const HistoryEnv = "SIMPLECALC_HISTORY"
+
+var (
+	ErrHistoryPath   = fmt.Errorf("error finding history file")
+	ErrHistoryFile   = fmt.Errorf("error accessing history file")
+	ErrEventNotFound = fmt.Errorf("event not found")
 )
 
 type History struct {
 	history []string
+
+	// maxLen is the maximum number of entries, 0 for unlimited
+	maxLen int
+
+	// file is where the new entries are appended, empty if not persistent
+	file string
+	err  error
+}
+
+// NewHistory creates an in-memory history keeping the last maxLen entries
+func NewHistory(maxLen int) *History {
+	return &History{
+		history: make([]string, 0),
+		maxLen:  maxLen,
+	}
+}
+
+// HistoryPath returns the path of the history file from $SIMPLECALC_HISTORY,
+// or simplecalc/history in $XDG_STATE_HOME, which is ~/.local/state by default.
+func HistoryPath() (string, error) {
+	if path := os.Getenv(HistoryEnv); path != "" {
+		return path, nil
+	}
+
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" || !filepath.IsAbs(dir) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("%w: %w", ErrHistoryPath, err)
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+
+	return filepath.Join(dir, "simplecalc", "history"), nil
 }
 
 func (h *History) Add(entry string) {
-	// Not to add empty entries to history
-	if entry == "" {
+	// Not to add empty entries or the same entry as the last one to history
+	if entry == "" || (len(h.history) > 0 && h.history[len(h.history)-1] == entry) {
 		return
 	}
 
 	h.history = append(h.history, entry)
+	if h.maxLen > 0 && len(h.history) > h.maxLen {
+		h.history = h.history[len(h.history)-h.maxLen:]
+	}
+
+	if h.file != "" {
+		if err := appendHistory(h.file, entry); err != nil {
+			// Stop persisting instead of failing every entry
+			h.file = ""
+			h.err = err
+		}
+	}
+}
+
+// SetMaxLen sets the maximum number of entries, 0 for unlimited,
+// and removes the oldest entries beyond it.
+func (h *History) SetMaxLen(maxLen int) {
+	h.maxLen = maxLen
+	if maxLen > 0 && len(h.history) > maxLen {
+		h.history = h.history[len(h.history)-maxLen:]
+	}
+}
+
+// Load reads the entries from the file and appends the new entries to it,
+// the file is created if not exists and shortened if it is too long.
+// The file is locked until it's shortened, so the entries appended
+// by the other sessions in the meantime are not lost.
+func (h *History) Load(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("%w: %w", ErrHistoryFile, err)
+	}
+
+	unlock, err := lockHistory(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	f, err := os.OpenFile(path, os.O_RDONLY|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrHistoryFile, err)
+	}
+	defer f.Close()
+
+	lines := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		h.Add(scanner.Text())
+		lines++
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("%w: %w", ErrHistoryFile, err)
+	}
+
+	if lines > len(h.history) {
+		if err := writeHistory(path, h.history); err != nil {
+			return err
+		}
+	}
+
+	h.file = path
+	return nil
+}
+
+// Err returns the error stopping the entries from being appended to the file
+func (h *History) Err() error {
+	return h.err
 }
 
 func (h *History) Len() int {
@@ -30,8 +148,67 @@ func (h *History) At(idx int) string {
 	return h.history[len(h.history)-idx-1]
 }
 
+// Clear removes all the entries, including the ones in the file
 func (h *History) Clear() {
 	h.history = []string{}
+
+	if h.file != "" {
+		if err := clearHistory(h.file); err != nil {
+			h.file = ""
+			h.err = err
+		}
+	}
+}
+
+// Expand replaces the recall of the history like "!!" with the entry,
+// "!n" is the entry numbered n in the listing and "!prefix" is
+// the newest entry starting with the prefix. It returns false
+// if the input is not a recall or no entry matches it, then the input
+// is evaluated normally, like the logical not "!0" or "!flag".
+// The logical not like "!1" or "!x" is still recalled if an entry
+// matches it, but "! 1", "! x" and "!(x)" are never recalled.
+func (h *History) Expand(input string) (string, bool, error) {
+	input = strings.TrimSpace(input)
+	event, ok := strings.CutPrefix(input, "!")
+	if !ok || event == "" || strings.ContainsFunc(event, unicode.IsSpace) {
+		return input, false, nil
+	}
+
+	first := []rune(event)[0]
+	switch {
+	case event == "!":
+		// "!!" is never an expression
+		if len(h.history) == 0 {
+			return "", true, fmt.Errorf("%w: '%s'", ErrEventNotFound, input)
+		}
+		return h.history[len(h.history)-1], true, nil
+	case unicode.IsDigit(first):
+		n, err := strconv.Atoi(event)
+		if err == nil && n >= 1 && n <= len(h.history) {
+			return h.history[n-1], true, nil
+		}
+	case unicode.IsLetter(first) || first == '_':
+		for i := len(h.history) - 1; i >= 0; i-- {
+			if strings.HasPrefix(h.history[i], event) {
+				return h.history[i], true, nil
+			}
+		}
+	}
+
+	return input, false, nil
+}
+
+// List returns the entries numbered from the oldest one for "!n"
+func (h *History) List() string {
+	var sb strings.Builder
+	for i, line := range h.history {
+		if i > 0 {
+			sb.WriteString("\r\n")
+		}
+		fmt.Fprintf(&sb, "%5d  %s", i+1, line)
+	}
+
+	return sb.String()
 }
 
 func (h *History) String() string {
@@ -49,3 +226,81 @@ func (h *History) String() string {
 
 	return sb.String()
 }
+
+// lockHistory locks the file next to the history file, which is never replaced
+// unlike the history file, it returns the function to unlock it.
+func lockHistory(path string) (func(), error) {
+	f, err := os.OpenFile(path+".lock", os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrHistoryFile, err)
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%w: %w", ErrHistoryFile, err)
+	}
+
+	return func() {
+		unlockFile(f)
+		f.Close()
+	}, nil
+}
+
+// appendHistory appends the entry to the file with a single write
+// in the append mode, so the entries from the sessions running
+// at the same time are not interleaved. The file is locked
+// not to append while it's replaced by the other sessions.
+func appendHistory(path, entry string) error {
+	unlock, err := lockHistory(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrHistoryFile, err)
+	}
+
+	_, err = f.WriteString(entry + "\n")
+	err = errors.Join(err, f.Close())
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrHistoryFile, err)
+	}
+
+	return nil
+}
+
+func clearHistory(path string) error {
+	unlock, err := lockHistory(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return writeHistory(path, nil)
+}
+
+// writeHistory replaces the file with the entries by renaming a temporary file,
+// so the other sessions never read a partially written file.
+// The file must be locked by lockHistory.
+func writeHistory(path string, entries []string) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrHistoryFile, err)
+	}
+	defer os.Remove(f.Name())
+
+	w := bufio.NewWriter(f)
+	for _, entry := range entries {
+		w.WriteString(entry + "\n")
+	}
+	err = errors.Join(w.Flush(), f.Close())
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrHistoryFile, err)
+	}
+
+	return nil
+}
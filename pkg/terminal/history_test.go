@@ -1,6 +1,12 @@
 package terminal
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -30,6 +36,11 @@ func TestHistory_AddAndLen(t *testing.T) {
 			entries: []string{"a", "", "b"}, // the empty one should be ignored
 			wantLen: 2,
 		},
+		{
+			name:    "consecutive duplicates",
+			entries: []string{"a", "a", "b", "a"}, // only the second one should be ignored
+			wantLen: 3,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -130,3 +141,311 @@ func TestHistory_String(t *testing.T) {
 		t.Errorf("String() = %q, want %q", got, wantStr)
 	}
 }
+
+func TestHistory_MaxLen(t *testing.T) {
+	h := NewHistory(2)
+	for _, e := range []string{"one", "two", "three"} {
+		h.Add(e)
+	}
+
+	want := "two\r\nthree"
+	if got := h.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestHistory_Load(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		maxLen   int
+		add      []string
+		want     string
+		wantFile []string
+	}{
+		{
+			name:     "new file",
+			add:      []string{"1 + 2", "x = 3"},
+			want:     "1 + 2\r\nx = 3",
+			wantFile: []string{"1 + 2", "x = 3"},
+		},
+		{
+			name:     "existing entries",
+			content:  "a\nb\n",
+			add:      []string{"c"},
+			want:     "a\r\nb\r\nc",
+			wantFile: []string{"a", "b", "c"},
+		},
+		{
+			name:     "duplicates from other sessions",
+			content:  "a\na\nb\n",
+			add:      []string{"b", "c"},
+			want:     "a\r\nb\r\nc",
+			wantFile: []string{"a", "b", "c"},
+		},
+		{
+			name:     "too long file",
+			content:  "a\nb\nc\nd\n",
+			maxLen:   2,
+			add:      []string{"e"},
+			want:     "d\r\ne",
+			wantFile: []string{"c", "d", "e"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "state", "history")
+			if tt.content != "" {
+				if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			h := NewHistory(tt.maxLen)
+			if err := h.Load(path); err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			for _, e := range tt.add {
+				h.Add(e)
+			}
+			if err := h.Err(); err != nil {
+				t.Fatalf("Err() = %v", err)
+			}
+
+			if got := h.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+			if got := readLines(t, path); !slices.Equal(got, tt.wantFile) {
+				t.Errorf("file = %q, want %q", got, tt.wantFile)
+			}
+		})
+	}
+}
+
+func TestHistory_Load_Concurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	first, second := NewHistory(0), NewHistory(0)
+	if err := first.Load(path); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if err := second.Load(path); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	first.Add("a")
+	second.Add("b")
+	first.Add("c")
+
+	want := []string{"a", "b", "c"}
+	if got := readLines(t, path); !slices.Equal(got, want) {
+		t.Errorf("file = %q, want %q", got, want)
+	}
+}
+
+func TestHistory_Load_ConcurrentAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	second := NewHistory(0)
+	if err := second.Load(path); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	// Append from the second session while the first one shortens the file
+	const count = 200
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range count {
+			second.Add(fmt.Sprintf("b%d", i))
+		}
+	}()
+
+	for range 50 {
+		if err := appendHistory(path, strings.Repeat("a\n", 10)+"a"); err != nil {
+			t.Fatal(err)
+		}
+		if err := NewHistory(0).Load(path); err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+	}
+	<-done
+
+	if err := second.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+
+	got := slices.DeleteFunc(readLines(t, path), func(line string) bool { return line == "a" })
+	want := make([]string, 0, count)
+	for i := range count {
+		want = append(want, fmt.Sprintf("b%d", i))
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("entries of second session = %q, want %q", got, want)
+	}
+}
+
+func TestHistory_Clear_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	h := NewHistory(0)
+	if err := h.Load(path); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	h.Add("a")
+	h.Clear()
+	h.Add("b")
+
+	want := []string{"b"}
+	if got := readLines(t, path); !slices.Equal(got, want) {
+		t.Errorf("file = %q, want %q", got, want)
+	}
+}
+
+func TestHistoryPath(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		xdg  string
+		home string
+		want string
+	}{
+		{
+			name: "environment variable",
+			env:  "/tmp/calc_history",
+			xdg:  "/state",
+			want: "/tmp/calc_history",
+		},
+		{
+			name: "XDG state home",
+			xdg:  "/state",
+			want: "/state/simplecalc/history",
+		},
+		{
+			name: "default",
+			home: "/home/user",
+			want: "/home/user/.local/state/simplecalc/history",
+		},
+		{
+			name: "relative XDG state home",
+			xdg:  "state",
+			home: "/home/user",
+			want: "/home/user/.local/state/simplecalc/history",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(HistoryEnv, tt.env)
+			t.Setenv("XDG_STATE_HOME", tt.xdg)
+			t.Setenv("HOME", tt.home)
+
+			got, err := HistoryPath()
+			if err != nil {
+				t.Fatalf("HistoryPath() error = %v", err)
+			}
+			if got != filepath.FromSlash(tt.want) {
+				t.Errorf("HistoryPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func readLines(t *testing.T, path string) []string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("os.ReadFile failed: %v", err)
+	}
+
+	return strings.FieldsFunc(string(data), func(r rune) bool {
+		return r == '\n'
+	})
+}
+
+func TestHistory_Expand(t *testing.T) {
+	var h History
+	for _, e := range []string{"x = 5", "sqrt(16)", "x * 2"} {
+		h.Add(e)
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantOk  bool
+		wantErr error
+	}{
+		{name: "last", input: "!!", want: "x * 2", wantOk: true},
+		{name: "number", input: "!2", want: "sqrt(16)", wantOk: true},
+		{name: "prefix", input: "!x", want: "x * 2", wantOk: true},
+		{name: "function prefix", input: "  !sq ", want: "sqrt(16)", wantOk: true},
+		// The logical not is evaluated if no entry matches it
+		{name: "longer prefix", input: "!x=", want: "!x="},
+		{name: "number out of range", input: "!4", want: "!4"},
+		{name: "zero", input: "!0", want: "!0"},
+		{name: "not found", input: "!flag", want: "!flag"},
+		// The logical not of a number is recalled if the entry exists
+		{name: "ambiguous number", input: "!1", want: "x = 5", wantOk: true},
+		{name: "logical not with space", input: "! x", want: "! x"},
+		{name: "logical not of group", input: "!(x > 3)", want: "!(x > 3)"},
+		{name: "expression", input: "1 + 2", want: "1 + 2"},
+		{name: "not equal", input: "x != 3", want: "x != 3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := h.Expand(tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expand(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if ok != tt.wantOk {
+				t.Errorf("Expand(%q) ok = %v, want %v", tt.input, ok, tt.wantOk)
+			}
+			if got != tt.want {
+				t.Errorf("Expand(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHistory_Expand_Empty(t *testing.T) {
+	var h History
+	if _, ok, err := h.Expand("!!"); !ok || !errors.Is(err, ErrEventNotFound) {
+		t.Errorf("Expand(%q) = %v, %v, want %v", "!!", ok, err, ErrEventNotFound)
+	}
+	if got, ok, err := h.Expand("!1"); ok || err != nil || got != "!1" {
+		t.Errorf("Expand(%q) = %q, %v, %v, want not a recall", "!1", got, ok, err)
+	}
+}
+
+func TestHistory_List(t *testing.T) {
+	var h History
+	if got := h.List(); got != "" {
+		t.Errorf("List() empty history = %q, want empty", got)
+	}
+
+	h.Add("x = 5")
+	h.Add("x * 2")
+	want := "    1  x = 5\r\n    2  x * 2"
+	if got := h.List(); got != want {
+		t.Errorf("List() = %q, want %q", got, want)
+	}
+}
+
+func TestHistory_SetMaxLen(t *testing.T) {
+	var h History
+	for _, e := range []string{"one", "two", "three"} {
+		h.Add(e)
+	}
+
+	h.SetMaxLen(2)
+	if want := "two\r\nthree"; h.String() != want {
+		t.Errorf("String() after SetMaxLen(2) = %q, want %q", h.String(), want)
+	}
+
+	// The limit applies to the new entries without the history file
+	h.Add("four")
+	if want := "three\r\nfour"; h.String() != want {
+		t.Errorf("String() after Add() = %q, want %q", h.String(), want)
+	}
+}
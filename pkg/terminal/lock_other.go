@@ -0,0 +1,17 @@
+//go:build !unix && !windows
+
+package terminal
+
+import (
+	"os"
+)
+
+// lockFile doesn't lock on the platforms without file locks,
+// the entries appended during the compaction may be lost there.
+func lockFile(f *os.File) error {
+	return nil
+}
+
+func unlockFile(f *os.File) error {
+	return nil
+}
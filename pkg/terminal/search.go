@@ -0,0 +1,146 @@
+package terminal
+
+import (
+	"strings"
+)
+
+const (
+	keyCtrlG = 7
+	keyCtrlR = 18
+)
+
+const (
+	searchPrompt       = "(reverse-i-search)'"
+	failedSearchPrompt = "(failed reverse-i-search)'"
+	searchSeparator    = "': "
+)
+
+// search is the reverse incremental search of the history started by Ctrl-R.
+//
+// The term package can't change the prompt while editing a line,
+// so the search is shown in the line like "(reverse-i-search)'sq': sqrt(2)"
+// with the cursor after the query, then the keys like Backspace
+// handled by the term edit the query directly.
+type search struct {
+	history *History
+	active  bool
+
+	query string
+	// match is the index of the matched entry for History.At, -1 if none
+	match int
+	// original is the line before the search, restored by Ctrl-G
+	original string
+}
+
+// handleKey is the AutoCompleteCallback of the term handling the keys
+// during the search, Ctrl-R searches the older entries, Ctrl-G cancels the search,
+// and the other control keys stop the search to edit the matched entry.
+func (s *search) handleKey(line string, pos int, key rune) (string, int, bool) {
+	if !s.active {
+		if key != keyCtrlR || s.history == nil {
+			return "", 0, false
+		}
+		s.active = true
+		s.query = ""
+		s.match = -1
+		s.original = line
+		newLine, newPos := s.render()
+		return newLine, newPos, true
+	}
+
+	// The line is not a search anymore after the keys like Up or Ctrl-U
+	if !s.update(line) {
+		s.active = false
+		return "", 0, false
+	}
+
+	switch {
+	case key == keyCtrlR:
+		if match := s.find(s.match + 1); match >= 0 {
+			s.match = match
+		}
+	case key == keyCtrlG:
+		s.active = false
+		return s.original, len(s.original), true
+	case key >= 32 && key < 0xd800:
+		s.query += string(key)
+		s.match = s.find(max(s.match, 0))
+	default:
+		s.active = false
+		entry := s.entry()
+		return entry, len(entry), true
+	}
+
+	newLine, newPos := s.render()
+	return newLine, newPos, true
+}
+
+// accept stops the search when the line is entered,
+// it returns the matched entry, or the line if not searching.
+func (s *search) accept(line string) string {
+	if !s.active {
+		return line
+	}
+
+	s.active = false
+	if !s.update(line) {
+		return line
+	}
+	return s.entry()
+}
+
+// update reads the query from the line edited by the term,
+// it returns false if the line is not a search.
+func (s *search) update(line string) bool {
+	rest, ok := strings.CutPrefix(line, searchPrompt)
+	if !ok {
+		rest, ok = strings.CutPrefix(line, failedSearchPrompt)
+	}
+	if !ok {
+		return false
+	}
+
+	query, _, ok := strings.Cut(rest, searchSeparator)
+	if !ok {
+		return false
+	}
+
+	if query != s.query {
+		s.query = query
+		s.match = s.find(0)
+	}
+	return true
+}
+
+// find returns the index of the newest entry containing the query
+// from the start index, or -1 if none
+func (s *search) find(start int) int {
+	if s.query == "" {
+		return -1
+	}
+
+	for i := start; i < s.history.Len(); i++ {
+		if strings.Contains(s.history.At(i), s.query) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *search) entry() string {
+	if s.match < 0 {
+		return ""
+	}
+	return s.history.At(s.match)
+}
+
+// render returns the line showing the search and the cursor position after the query
+func (s *search) render() (string, int) {
+	prompt := searchPrompt
+	if s.query != "" && s.match < 0 {
+		prompt = failedSearchPrompt
+	}
+
+	line := prompt + s.query
+	return line + searchSeparator + s.entry(), len(line)
+}
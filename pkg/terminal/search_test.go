@@ -0,0 +1,122 @@
+package terminal
+
+import (
+	"testing"
+)
+
+func TestSearch_HandleKey(t *testing.T) {
+	h := NewHistory(0)
+	for _, e := range []string{"sqrt(2)", "x = 5", "sqrt(16) + x", "y = 1"} {
+		h.Add(e)
+	}
+
+	// press types the keys into the line like the term,
+	// the Backspace is handled by the term directly
+	press := func(s *search, line string, keys ...rune) string {
+		for _, key := range keys {
+			if key == '\b' {
+				line = line[:len(line)-1]
+				continue
+			}
+			newLine, _, ok := s.handleKey(line, len(line), key)
+			if ok {
+				line = newLine
+			} else {
+				line += string(key)
+			}
+		}
+		return line
+	}
+
+	tests := []struct {
+		name       string
+		line       string
+		keys       []rune
+		wantLine   string
+		wantAccept string
+	}{
+		{
+			name:       "not searching",
+			keys:       []rune("1+1"),
+			wantLine:   "1+1",
+			wantAccept: "1+1",
+		},
+		{
+			name:       "start search",
+			keys:       []rune{keyCtrlR},
+			wantLine:   "(reverse-i-search)'': ",
+			wantAccept: "",
+		},
+		{
+			name:       "newest match",
+			keys:       []rune{keyCtrlR, 's', 'q'},
+			wantLine:   "(reverse-i-search)'sq': sqrt(16) + x",
+			wantAccept: "sqrt(16) + x",
+		},
+		{
+			name:       "older match",
+			keys:       []rune{keyCtrlR, 's', 'q', keyCtrlR},
+			wantLine:   "(reverse-i-search)'sq': sqrt(2)",
+			wantAccept: "sqrt(2)",
+		},
+		{
+			name:       "no older match",
+			keys:       []rune{keyCtrlR, 's', 'q', keyCtrlR, keyCtrlR},
+			wantLine:   "(reverse-i-search)'sq': sqrt(2)",
+			wantAccept: "sqrt(2)",
+		},
+		{
+			name:       "failed",
+			keys:       []rune{keyCtrlR, 'z'},
+			wantLine:   "(failed reverse-i-search)'z': ",
+			wantAccept: "",
+		},
+		{
+			name:       "cancel",
+			line:       "2 *",
+			keys:       []rune{keyCtrlR, 'x', keyCtrlG},
+			wantLine:   "2 *",
+			wantAccept: "2 *",
+		},
+		{
+			name:       "edit match",
+			keys:       []rune{keyCtrlR, '=', '\t', '0'},
+			wantLine:   "y = 10",
+			wantAccept: "y = 10",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &search{history: h}
+			line := press(s, tt.line, tt.keys...)
+			if line != tt.wantLine {
+				t.Errorf("line = %q, want %q", line, tt.wantLine)
+			}
+			if got := s.accept(line); got != tt.wantAccept {
+				t.Errorf("accept() = %q, want %q", got, tt.wantAccept)
+			}
+		})
+	}
+}
+
+func TestSearch_Accept_EditedQuery(t *testing.T) {
+	h := NewHistory(0)
+	for _, e := range []string{"x = 5", "sqrt(16) + x"} {
+		h.Add(e)
+	}
+
+	s := &search{history: h}
+	line := ""
+	for _, key := range []rune{keyCtrlR, 'x', ' '} {
+		line, _, _ = s.handleKey(line, len(line), key)
+	}
+	if want := "(reverse-i-search)'x ': x = 5"; line != want {
+		t.Fatalf("line = %q, want %q", line, want)
+	}
+
+	// Backspace removes the last character of the query
+	line = "(reverse-i-search)'x': x = 5"
+	if got, want := s.accept(line), "sqrt(16) + x"; got != want {
+		t.Errorf("accept() = %q, want %q", got, want)
+	}
+}
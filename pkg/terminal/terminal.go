@@ -3,6 +3,7 @@ package terminal
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"golang.org/x/term"
 )
@@ -19,7 +20,46 @@ type Terminal struct {
 	oldState  *term.State
 	terminal  *term.Terminal
 	history   *History
+	search    *search
+	completer *completer
 	inputFile *os.File
+
+	// recalled is the line resolved when the term adds it to the history
+	recalled *recall
+}
+
+// recall is the line entered by the search or expanded from the recall like "!!"
+type recall struct {
+	line string
+	err  error
+}
+
+// recorder is the history of the term, which adds the recalled entries
+// instead of the lines like "!!" to the history
+type recorder struct {
+	*Terminal
+}
+
+func (r recorder) Add(entry string) {
+	line, err := r.resolve(entry)
+	r.recalled = &recall{line: line, err: err}
+	if err == nil {
+		r.history.Add(line)
+	}
+}
+
+func (r recorder) Len() int {
+	return r.history.Len()
+}
+
+func (r recorder) At(idx int) string {
+	return r.history.At(idx)
+}
+
+// IsTerminal reports whether the file is a terminal,
+// the input is piped or redirected if not.
+func IsTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
 }
 
 func NewTerminal(f *os.File, prompt string) (*Terminal, error) {
@@ -37,28 +77,88 @@ func NewTerminal(f *os.File, prompt string) (*Terminal, error) {
 		return nil, ErrCreateTerminal
 	}
 
-	// Set the history instance
-	h := &History{
-		history: make([]string, 0),
-	}
-	t.History = h
-
-	return &Terminal{
+	// Set the history instance, the search by Ctrl-R and the completion by Tab
+	h := NewHistory(DefaultHistorySize)
+	trm := &Terminal{
 		prompt:    prompt,
 		oldState:  oldState,
 		terminal:  t,
 		history:   h,
+		search:    &search{history: h},
+		completer: &completer{},
 		inputFile: f,
-	}, nil
+	}
+	t.History = recorder{trm}
+	t.AutoCompleteCallback = trm.handleKey
+
+	return trm, nil
+}
+
+// SetCompleter sets the candidates completed by Tab, the commands are
+// only completed at the beginning of the line, and the names are
+// returned by the function for each completion like the variables.
+func (t *Terminal) SetCompleter(commands []string, names func() []string) {
+	if t.completer == nil {
+		return
+	}
+
+	t.completer.commands = commands
+	t.completer.names = names
 }
 
-// ReadLine wraps the ReadLine method of the term
+// handleKey is the AutoCompleteCallback of the term
+func (t *Terminal) handleKey(line string, pos int, key rune) (string, int, bool) {
+	if t.search != nil {
+		if newLine, newPos, ok := t.search.handleKey(line, pos, key); ok {
+			return newLine, newPos, true
+		}
+	}
+	if t.completer != nil {
+		return t.completer.handleKey(line, pos, key)
+	}
+
+	return "", 0, false
+}
+
+// ReadLine wraps the ReadLine method of the term, the line from the search
+// or the recall like "!!" is printed and returned instead.
 func (t *Terminal) ReadLine() (string, error) {
-	line, err := t.terminal.ReadLine()
+	input, err := t.terminal.ReadLine()
 	if err != nil {
 		return "", err
 	}
-	return line, nil
+
+	// The term resolves the line when adding it to the history
+	r := t.recalled
+	t.recalled = nil
+	if r == nil {
+		line, err := t.resolve(input)
+		r = &recall{line: line, err: err}
+	}
+	if r.err != nil {
+		return "", r.err
+	}
+
+	if r.line != strings.TrimSpace(input) {
+		fmt.Fprintf(t.terminal, "%s\r\n", r.line)
+	}
+	return r.line, nil
+}
+
+// resolve returns the entry matched by the search or recalled by the input
+func (t *Terminal) resolve(input string) (string, error) {
+	if t.search != nil {
+		input = t.search.accept(input)
+	}
+	if t.history == nil {
+		return input, nil
+	}
+
+	line, ok, err := t.history.Expand(input)
+	if !ok {
+		return input, nil
+	}
+	return line, err
 }
 
 // Restore wraps the Restore method of the term
@@ -76,6 +176,44 @@ func (t *Terminal) Restore() error {
 	return nil
 }
 
+// SetHistorySize keeps the last maxLen entries of the history,
+// with or without the history file.
+func (t *Terminal) SetHistorySize(maxLen int) {
+	if t.history == nil {
+		return
+	}
+
+	t.history.SetMaxLen(maxLen)
+}
+
+// LoadHistory loads the entries of the history from the file
+// and appends the new entries to it.
+func (t *Terminal) LoadHistory(path string) error {
+	if t.history == nil {
+		return nil
+	}
+
+	return t.history.Load(path)
+}
+
+// HistoryError returns the error writing the history file
+func (t *Terminal) HistoryError() error {
+	if t.history == nil {
+		return nil
+	}
+
+	return t.history.Err()
+}
+
+// ListHistory returns the numbered entries of the history
+func (t *Terminal) ListHistory() string {
+	if t.history == nil {
+		return ""
+	}
+
+	return t.history.List()
+}
+
 func (t *Terminal) GetHistory() string {
 	if t.history == nil {
 		return ""
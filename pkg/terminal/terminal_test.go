@@ -121,3 +121,90 @@ func TestNewTerminal_Error(t *testing.T) {
 		t.Fatalf("NewTerminal() expected error for non-terminal file, got nil, trm=%v", trm)
 	}
 }
+
+func TestIsTerminal(t *testing.T) {
+	f, err := os.OpenFile(os.DevNull, os.O_RDONLY, 0)
+	if err != nil {
+		t.Fatalf("os.OpenFile failed: %v", err)
+	}
+	defer f.Close()
+
+	if IsTerminal(f) {
+		t.Errorf("IsTerminal(%s) = true, want false", os.DevNull)
+	}
+}
+
+func TestTerminal_ReadLine_Recall(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		want        []string
+		wantErr     error
+		wantHistory string
+	}{
+		{
+			name:        "last entry",
+			input:       "x = 5\r!!\r",
+			want:        []string{"x = 5", "x = 5"},
+			wantHistory: "x = 5",
+		},
+		{
+			name:        "numbered entry",
+			input:       "1 + 1\r2 + 2\r!1\r",
+			want:        []string{"1 + 1", "2 + 2", "1 + 1"},
+			wantHistory: "1 + 1\r\n2 + 2\r\n1 + 1",
+		},
+		{
+			name:        "search",
+			input:       "sqrt(2)\r1 + 1\r\x12sq\r",
+			want:        []string{"sqrt(2)", "1 + 1", "sqrt(2)"},
+			wantHistory: "sqrt(2)\r\n1 + 1\r\nsqrt(2)",
+		},
+		{
+			name:        "logical not without matching entry",
+			input:       "1 + 1\r!y\r",
+			want:        []string{"1 + 1", "!y"},
+			wantHistory: "1 + 1\r\n!y",
+		},
+		{
+			name:        "event not found",
+			input:       "!!\r",
+			wantErr:     ErrEventNotFound,
+			wantHistory: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			termOut := &bytes.Buffer{}
+			tTerm := term.NewTerminal(struct {
+				io.Reader
+				io.Writer
+			}{bytes.NewBufferString(tt.input), termOut}, "> ")
+
+			h := NewHistory(0)
+			s := &search{history: h}
+			trm := &Terminal{terminal: tTerm, history: h, search: s}
+			tTerm.AutoCompleteCallback = s.handleKey
+			tTerm.History = recorder{trm}
+
+			for _, want := range tt.want {
+				line, err := trm.ReadLine()
+				if err != nil {
+					t.Fatalf("ReadLine() error = %v", err)
+				}
+				if line != want {
+					t.Errorf("ReadLine() = %q, want %q", line, want)
+				}
+			}
+			if tt.wantErr != nil {
+				if _, err := trm.ReadLine(); !errors.Is(err, tt.wantErr) {
+					t.Errorf("ReadLine() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			}
+
+			if got := trm.GetHistory(); got != tt.wantHistory {
+				t.Errorf("GetHistory() = %q, want %q", got, tt.wantHistory)
+			}
+		})
+	}
+}